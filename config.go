@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/subut0n/mk/internal/ansi"
+	"github.com/subut0n/mk/internal/config"
+	"github.com/subut0n/mk/internal/i18n"
+	"github.com/subut0n/mk/internal/theme"
+	"github.com/subut0n/mk/internal/ui"
+)
+
+// configCommand handles `mk config get|set|list|reset`, the scriptable
+// counterpart of the setup wizard.
+func configCommand(args []string) {
+	cfg := loadConfigAndSetLang()
+	m := i18n.Get()
+
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		listConfig(cfg)
+	case args[0] == "get" && len(args) == 2:
+		value, err := cfg.Get(args[1])
+		if err != nil {
+			exitConfigError(err)
+		}
+		fmt.Println(value)
+	case args[0] == "set" && len(args) == 3:
+		if err := cfg.Set(args[1], args[2]); err != nil {
+			exitConfigError(err)
+		}
+		fmt.Printf("%s%s%s\n", ansi.Green, fmt.Sprintf(m.ConfigSet, args[1], args[2]), ansi.Reset)
+		warnOverridden(cfg, args[1])
+	case args[0] == "reset" && len(args) == 2:
+		if err := cfg.Reset(args[1]); err != nil {
+			exitConfigError(err)
+		}
+		fmt.Printf("%s%s%s\n", ansi.Green, fmt.Sprintf(m.ConfigResetKey, args[1]), ansi.Reset)
+		warnOverridden(cfg, args[1])
+	case args[0] == "reset" && len(args) == 1:
+		if ui.IsTerminal(os.Stdin) {
+			fmt.Printf("%s%s%s", ansi.Gray, fmt.Sprintf(m.ConfigResetConfirm, cfg.Path()), ansi.Reset)
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if !isYes(answer) {
+				fmt.Printf("%s%s%s\n", ansi.Gray, m.Cancelled, ansi.Reset)
+				return
+			}
+		}
+		if err := cfg.Reset(""); err != nil {
+			fatal(m.ErrSaveConfig, err)
+		}
+		fmt.Printf("%s%s%s\n", ansi.Green, m.ConfigResetAll, ansi.Reset)
+	default:
+		fatal("%s", m.ErrConfigUsage)
+	}
+}
+
+// listConfig prints every setting with its effective value; values set by a
+// project file or an environment variable are marked with their source.
+func listConfig(cfg *config.Manager) {
+	keys := config.Keys()
+	width := 0
+	for _, key := range keys {
+		width = max(width, len(key))
+	}
+	for _, key := range keys {
+		value, _ := cfg.Get(key)
+		source := ""
+		if src := cfg.Source(key); src != "" && src != cfg.Path() {
+			source = fmt.Sprintf("  %s(%s)%s", ansi.Gray, homePath(src), ansi.Reset)
+		}
+		fmt.Println(strings.TrimRight(fmt.Sprintf("%-*s  %s%s", width, key, value, source), " "))
+	}
+}
+
+// warnOverridden tells the user that the setting just saved has no effect
+// here because a project file or an environment variable overrides it.
+func warnOverridden(cfg *config.Manager, key string) {
+	if src := cfg.Source(key); src != "" && src != cfg.Path() {
+		fmt.Fprintf(os.Stderr, "%s%s%s\n", ansi.Gray, fmt.Sprintf(i18n.Get().WarnSettingOverridden, key, homePath(src)), ansi.Reset)
+	}
+}
+
+// exitConfigError explains why a setting could not be read or changed.
+func exitConfigError(err error) {
+	m := i18n.Get()
+	var unknown *config.UnknownKeyError
+	var invalid *config.InvalidValueError
+	switch {
+	case errors.As(err, &unknown):
+		msg := fmt.Sprintf(m.ErrUnknownSetting, unknown.Key)
+		if unknown.Suggestion != "" {
+			msg += " " + fmt.Sprintf(m.HintDidYouMean, unknown.Suggestion)
+		}
+		fmt.Fprintf(os.Stderr, "%s%s%s\n", ansi.Red, msg, ansi.Reset)
+		fmt.Fprintf(os.Stderr, "%s%s%s\n", ansi.Gray, m.HintConfigList, ansi.Reset)
+		os.Exit(1)
+	case errors.As(err, &invalid) && len(invalid.Allowed) > 0:
+		fatal(m.ErrSettingChoice, invalid.Value, invalid.Key, strings.Join(invalid.Allowed, ", "))
+	case errors.As(err, &invalid):
+		fatal(m.ErrSettingValue, invalid.Value, invalid.Key, invalid.Err)
+	default:
+		fatal(m.ErrSaveConfig, err)
+	}
+}
+
+// showConfig handles `mk --config --show`: every effective setting with the
+// file it comes from, the user config.json or a project .mk.json.
+func showConfig(cfg *config.Manager) {
+	m := i18n.Get()
+
+	settings := cfg.Settings()
+	keyWidth, valueWidth := 0, 0
+	for _, s := range settings {
+		keyWidth = max(keyWidth, len(s.Key))
+		valueWidth = max(valueWidth, len(s.Value))
+	}
+
+	fmt.Printf("%s%s%s%s\n\n", ansi.Bold, ansi.Purple, m.ConfigShowTitle, ansi.Reset)
+	for _, s := range settings {
+		source := m.ConfigSourceDefault
+		if s.Source != "" {
+			source = homePath(s.Source)
+		}
+		fmt.Printf("  %s%-*s%s  %-*s  %s%s%s\n",
+			ansi.Bold, keyWidth, s.Key, ansi.Reset,
+			valueWidth, s.Value,
+			ansi.Gray, source, ansi.Reset,
+		)
+	}
+}
+
+// setupOptions returns the options that let the setup ask its questions in
+// menus that follow the key scheme and colors of cfg, and preview color
+// schemes as they look on this terminal.
+func setupOptions(cfg *config.Manager) config.SetupOptions {
+	queryBackground(cfg)
+	opts := ui.Options{
+		KeyScheme:     cfg.Config.KeyScheme,
+		ColorPalette:  getPalette(cfg.Config.ColorScheme),
+		CustomUpKey:   cfg.Config.CustomUpKey,
+		CustomDownKey: cfg.Config.CustomDownKey,
+	}
+	return config.SetupOptions{
+		Select: func(step config.SetupStep) (int, error) {
+			return ui.SelectStep(step, opts)
+		},
+		Theme: func(cs config.ColorScheme) theme.Theme {
+			return config.ThemeOf(cs, cfg.Config.Themes).Resolve(lightBackground)
+		},
+		Depth: colorDepth,
+	}
+}
+
+// exitSetup ends mk when the setup did not complete, reporting err with
+// format unless the user cancelled it. Nothing is saved.
+func exitSetup(format string, err error) {
+	if errors.Is(err, config.ErrSetupCancelled) {
+		fmt.Printf("%s%s%s\n", ansi.Gray, i18n.Get().Cancelled, ansi.Reset)
+		os.Exit(0)
+	}
+	fatal(format, err)
+}
+
+// homePath abbreviates the home directory to ~ in path.
+func homePath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	if rest, ok := strings.CutPrefix(path, home+string(filepath.Separator)); ok {
+		return filepath.Join("~", rest)
+	}
+	return path
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/subut0n/mk/internal/ansi"
+	"github.com/subut0n/mk/internal/history"
+	"github.com/subut0n/mk/internal/i18n"
+	"github.com/subut0n/mk/internal/ui"
+)
+
+// historyCommand handles `mk --history [export|import|clear ...]`. Without a
+// subcommand the history is browsed interactively in a terminal, or listed
+// otherwise.
+func historyCommand(args []string) {
+	cfg := loadConfigAndSetLang()
+	m := i18n.Get()
+
+	if len(args) == 0 {
+		if ui.IsTerminal(os.Stdin) && ui.IsTerminal(os.Stdout) && !ansi.Dumb() {
+			browseHistory(cfg)
+		} else {
+			showHistory()
+		}
+		return
+	}
+
+	switch args[0] {
+	case "export":
+		exportHistory(args[1:])
+	case "import":
+		importHistory(args[1:])
+	case "clear":
+		clearHistory(args[1:])
+	default:
+		fatal(m.ErrHistoryUsage, args[0])
+	}
+}
+
+// exportHistory handles `mk --history export`: the matching entries are
+// written as JSON or CSV to stdout or to the file given with -o.
+func exportHistory(args []string) {
+	m := i18n.Get()
+
+	var (
+		f              history.Filter
+		format, output string
+	)
+	for len(args) > 0 {
+		name, value, rest := nextOption(args, m.ErrHistoryExportUsage)
+		args = rest
+		switch name {
+		case "--format":
+			format = value
+		case "-o", "--output":
+			output = value
+		default:
+			if !filterOption(&f, name, value) {
+				fatal(m.ErrHistoryExportUsage, name)
+			}
+		}
+	}
+
+	if format == "" {
+		format = "json"
+		if strings.EqualFold(filepath.Ext(output), ".csv") {
+			format = "csv"
+		}
+	}
+	write := history.WriteJSON
+	switch format {
+	case "json":
+	case "csv":
+		write = history.WriteCSV
+	default:
+		fatal(m.ErrInvalidFormat, format)
+	}
+
+	hist, err := openHistory()
+	if err != nil {
+		fatal(m.ErrReadHistory, err)
+	}
+	entries := hist.Select(f)
+
+	if output == "" || output == "-" {
+		if err := write(os.Stdout, entries); err != nil {
+			fatal(m.ErrHistoryExport, err)
+		}
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := write(&buf, entries); err != nil {
+		fatal(m.ErrHistoryExport, err)
+	}
+	if err := os.WriteFile(output, buf.Bytes(), 0600); err != nil {
+		fatal(m.ErrHistoryExport, err)
+	}
+	fmt.Printf("%s%s%s\n", ansi.Green, fmt.Sprintf(m.HistoryExported, len(entries), output), ansi.Reset)
+}
+
+// importHistory handles `mk --history import FILE`: entries exported on
+// another machine (JSON, JSON Lines or CSV) are merged into the history.
+// Runs already recorded are skipped, so a file can be imported twice.
+func importHistory(args []string) {
+	m := i18n.Get()
+	if len(args) != 1 {
+		fatal("%s", m.ErrHistoryImportUsage)
+	}
+
+	var r io.Reader = os.Stdin
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			fatal(m.ErrHistoryImport, err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	entries, err := history.ReadEntries(r)
+	if err != nil {
+		fatal(m.ErrHistoryImport, err)
+	}
+
+	hist, err := openHistory()
+	if err != nil {
+		fatal(m.ErrReadHistory, err)
+	}
+	added, err := hist.Import(entries)
+	if err != nil {
+		fatal(m.ErrHistoryImport, err)
+	}
+	fmt.Printf("%s%s%s\n", ansi.Green, fmt.Sprintf(m.HistoryImported, added, len(entries)-added), ansi.Reset)
+}
+
+// clearHistory handles `mk --history clear`: the entries matching the filter
+// options are removed, or with --pruned those of deleted directories.
+// Clearing the whole history asks for confirmation in a terminal unless --yes
+// is given.
+func clearHistory(args []string) {
+	m := i18n.Get()
+
+	var f history.Filter
+	confirmed := !ui.IsTerminal(os.Stdin)
+	pruned := false
+	for len(args) > 0 {
+		switch args[0] {
+		case "-y", "--yes":
+			confirmed = true
+			args = args[1:]
+			continue
+		case "--pruned":
+			pruned = true
+			args = args[1:]
+			continue
+		}
+		name, value, rest := nextOption(args, m.ErrHistoryClearUsage)
+		args = rest
+		if !filterOption(&f, name, value) {
+			fatal(m.ErrHistoryClearUsage, name)
+		}
+	}
+
+	if pruned && f != (history.Filter{}) {
+		fatal(m.ErrHistoryClearUsage, "--pruned")
+	}
+
+	hist, err := openHistory()
+	if err != nil {
+		fatal(m.ErrReadHistory, err)
+	}
+	if pruned {
+		n, err := hist.Prune()
+		if err != nil {
+			fatal(m.ErrHistoryClear, err)
+		}
+		fmt.Printf("%s%s%s\n", ansi.Green, fmt.Sprintf(m.HistoryCleared, n), ansi.Reset)
+		return
+	}
+	if f == (history.Filter{}) && !confirmed {
+		fmt.Printf("%s%s%s", ansi.Gray, fmt.Sprintf(m.HistoryClearConfirm, len(hist.All())), ansi.Reset)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if !isYes(answer) {
+			fmt.Printf("%s%s%s\n", ansi.Gray, m.Cancelled, ansi.Reset)
+			return
+		}
+	}
+
+	n, err := hist.Clear(f)
+	if err != nil {
+		fatal(m.ErrHistoryClear, err)
+	}
+	fmt.Printf("%s%s%s\n", ansi.Green, fmt.Sprintf(m.HistoryCleared, n), ansi.Reset)
+}
+
+// filterOption applies a filter option shared by export and clear to f. It
+// reports false for other options.
+func filterOption(f *history.Filter, name, value string) bool {
+	m := i18n.Get()
+	switch name {
+	case "--project":
+		dir, err := filepath.Abs(value)
+		if err != nil {
+			fatal(m.ErrGeneric, err)
+		}
+		f.Project = dir
+	case "--since":
+		t, _, err := parseDate(value)
+		if err != nil {
+			fatal(m.ErrInvalidDate, value)
+		}
+		f.Since = t
+	case "--until":
+		t, day, err := parseDate(value)
+		if err != nil {
+			fatal(m.ErrInvalidDate, value)
+		}
+		if day {
+			// a bare date includes the whole day
+			t = t.AddDate(0, 0, 1)
+		}
+		f.Until = t
+	case "--status":
+		switch s := history.Status(value); s {
+		case history.StatusSuccess, history.StatusFailed, history.StatusInterrupted:
+			f.Status = s
+		default:
+			fatal(m.ErrInvalidStatus, value)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// nextOption splits the first option off args, accepting both "--name value"
+// and "--name=value". A missing value is reported with the usage message,
+// except for --project, whose directory defaults to the current one.
+func nextOption(args []string, usage string) (name, value string, rest []string) {
+	name, rest = args[0], args[1:]
+	if i := strings.Index(name, "="); i > 0 && strings.HasPrefix(name, "-") {
+		return name[:i], name[i+1:], rest
+	}
+	if name == "--project" && (len(rest) == 0 || strings.HasPrefix(rest[0], "-")) {
+		return name, ".", rest
+	}
+	if len(rest) == 0 || !strings.HasPrefix(name, "-") {
+		fatal(usage, name)
+	}
+	return name, rest[0], rest[1:]
+}
+
+// parseDate accepts a local date (YYYY-MM-DD), reported with day set, or an
+// RFC 3339 timestamp.
+func parseDate(s string) (t time.Time, day bool, err error) {
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, true, nil
+	}
+	t, err = time.Parse(time.RFC3339, s)
+	return t, false, err
+}
@@ -1,14 +1,24 @@
 package ansi
 
+// Color and text attribute sequences. The colors are the roles of the
+// active theme (see SetRoles), and all are empty strings while colors are
+// disabled (see SetColors).
+var (
+	Reset       = "\033[0m"
+	Bold        = "\033[1m"
+	Red         = "\033[31m" // errors
+	Green       = "\033[32m" // successes
+	Purple      = "\033[35m" // titles and menu numbers
+	Gray        = "\033[90m" // hints and other muted text
+	Cursor      = "\033[35m" // menu cursor
+	Description = "\033[90m" // target descriptions
+)
+
+// Cursor and screen control sequences, used whatever the color setting.
 const (
-	Reset      = "\033[0m"
-	Bold       = "\033[1m"
-	Red        = "\033[31m"
-	Green      = "\033[32m"
-	Purple     = "\033[35m"
-	Gray       = "\033[90m"
-	ClearLine  = "\033[2K"
-	Up         = "\033[1A"
-	HideCursor = "\033[?25l"
-	ShowCursor = "\033[?25h"
+	ClearLine   = "\033[2K"
+	ClearScreen = "\033[H\033[2J"
+	Up          = "\033[1A"
+	HideCursor  = "\033[?25l"
+	ShowCursor  = "\033[?25h"
 )
@@ -0,0 +1,74 @@
+package ansi
+
+import "os"
+
+// Roles holds the color sequences of the roles a theme defines.
+type Roles struct {
+	Title, Cursor, Description, Success, Error, Muted string
+}
+
+var colors = struct {
+	enabled     bool
+	reset, bold string
+	roles       Roles
+}{true, Reset, Bold, Roles{
+	Title:       Purple,
+	Cursor:      Cursor,
+	Description: Description,
+	Success:     Green,
+	Error:       Red,
+	Muted:       Gray,
+}}
+
+// ColorsWanted reports whether output should be colored, following the
+// NO_COLOR (https://no-color.org) and FORCE_COLOR conventions: FORCE_COLOR
+// set to anything but 0 enables colors; otherwise NO_COLOR, TERM=dumb, and
+// an output that is not a terminal disable them.
+func ColorsWanted(terminal bool) bool {
+	if v, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		return v != "0"
+	}
+	if os.Getenv("NO_COLOR") != "" || Dumb() {
+		return false
+	}
+	return terminal
+}
+
+// Dumb reports whether the terminal is declared unable to handle escape
+// sequences (TERM=dumb).
+func Dumb() bool {
+	return os.Getenv("TERM") == "dumb"
+}
+
+// SetColors enables or disables the color sequences.
+func SetColors(enabled bool) {
+	colors.enabled = enabled
+	if !enabled {
+		Reset, Bold, Red, Green, Purple, Gray, Cursor, Description = "", "", "", "", "", "", "", ""
+		return
+	}
+	r := colors.roles
+	Reset, Bold = colors.reset, colors.bold
+	Red, Green, Purple, Gray, Cursor, Description = r.Error, r.Success, r.Title, r.Muted, r.Cursor, r.Description
+}
+
+// SetRoles replaces the color sequences of the theme roles. They take
+// effect while colors are enabled.
+func SetRoles(r Roles) {
+	colors.roles = r
+	SetColors(colors.enabled)
+}
+
+// ColorsEnabled reports whether colors are enabled.
+func ColorsEnabled() bool {
+	return colors.enabled
+}
+
+// Color returns the color sequence code, or an empty string while colors
+// are disabled.
+func Color(code string) string {
+	if !colors.enabled {
+		return ""
+	}
+	return code
+}
@@ -0,0 +1,66 @@
+package ansi
+
+import (
+	"os"
+	"testing"
+)
+
+func TestColorsWanted(t *testing.T) {
+	cases := []struct {
+		force, noColor, term string
+		terminal, want       bool
+	}{
+		{terminal: true, want: true},
+		{terminal: false, want: false},
+		{noColor: "1", terminal: true, want: false},
+		{term: "dumb", terminal: true, want: false},
+		{force: "1", noColor: "1", terminal: false, want: true},
+		{force: "0", terminal: true, want: false},
+	}
+	for _, c := range cases {
+		t.Setenv("FORCE_COLOR", c.force)
+		if c.force == "" {
+			os.Unsetenv("FORCE_COLOR")
+		}
+		t.Setenv("NO_COLOR", c.noColor)
+		t.Setenv("TERM", c.term)
+		if got := ColorsWanted(c.terminal); got != c.want {
+			t.Errorf("FORCE_COLOR=%q NO_COLOR=%q TERM=%q terminal=%v: got %v, want %v",
+				c.force, c.noColor, c.term, c.terminal, got, c.want)
+		}
+	}
+}
+
+func TestSetColors(t *testing.T) {
+	defer SetColors(true)
+
+	SetColors(false)
+	if Red != "" || Reset != "" || Color("\033[33m") != "" {
+		t.Error("color sequences must be empty when disabled")
+	}
+	SetColors(true)
+	if Red != "\033[31m" || Color("\033[33m") != "\033[33m" {
+		t.Error("color sequences must be restored when enabled")
+	}
+}
+
+func TestSetRoles(t *testing.T) {
+	defaults := colors.roles
+	defer SetRoles(defaults)
+	defer SetColors(true)
+
+	roles := defaults
+	roles.Description = "\033[38;5;244m"
+	SetRoles(roles)
+	if Description != roles.Description || Gray != defaults.Muted {
+		t.Errorf("unexpected role sequences %q %q", Description, Gray)
+	}
+	SetColors(false)
+	if Description != "" {
+		t.Error("role sequences must be empty when colors are disabled")
+	}
+	SetColors(true)
+	if Description != roles.Description {
+		t.Error("the theme roles must be restored when colors are enabled")
+	}
+}
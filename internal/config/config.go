@@ -2,14 +2,21 @@ package config
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/subut0n/mk/internal/ansi"
 	"github.com/subut0n/mk/internal/i18n"
+	"github.com/subut0n/mk/internal/safefile"
+	"github.com/subut0n/mk/internal/theme"
 )
 
 // KeyScheme defines the keyboard navigation scheme.
@@ -21,95 +28,349 @@ const (
 	KeySchemeCustom KeyScheme = "custom"
 )
 
-// ColorScheme defines a color palette for the UI.
+// ColorScheme names the theme of the UI: one of the built-in schemes below
+// or a theme defined in the themes setting.
 type ColorScheme string
 
 const (
 	ColorSchemeRainbow      ColorScheme = "rainbow"
 	ColorSchemeDeuteranopia ColorScheme = "deuteranopia"
 	ColorSchemeTritanopia   ColorScheme = "tritanopia"
-	ColorSchemeHighContrast  ColorScheme = "high-contrast"
+	ColorSchemeHighContrast ColorScheme = "high-contrast"
+)
+
+// MenuOrder defines how targets are ordered in the interactive menu.
+type MenuOrder string
+
+const (
+	MenuOrderMakefile MenuOrder = "makefile" // Makefile order (default)
+	MenuOrderFrecency MenuOrder = "frecency" // most frequently and recently run first
+	MenuOrderRecent   MenuOrder = "recent"   // Makefile order below a "Recently used" group
+)
+
+// Background selects the light or dark variant of the theme.
+type Background string
+
+const (
+	BackgroundAuto  Background = "auto" // detected from the terminal (default)
+	BackgroundDark  Background = "dark"
+	BackgroundLight Background = "light"
 )
 
 // Config holds the user configuration.
 type Config struct {
+	Version       int         `json:"version,omitempty"` // schema version, see CurrentVersion
 	KeyScheme     KeyScheme   `json:"key_scheme"`
 	Language      i18n.Lang   `json:"language"`
 	ColorScheme   ColorScheme `json:"color_scheme"`
 	CustomUpKey   byte        `json:"custom_up_key,omitempty"`
 	CustomDownKey byte        `json:"custom_down_key,omitempty"`
+	MenuOrder     MenuOrder   `json:"menu_order,omitempty"`
+
+	// User-defined themes, selected by name with color_scheme
+	Themes     map[string]theme.Theme `json:"themes,omitempty"`
+	Background Background             `json:"background,omitempty"` // light or dark theme variant (default auto)
+
+	// Watch mode (mk --watch)
+	WatchPatterns   []string `json:"watch_patterns,omitempty"`    // glob patterns watched instead of the target's prerequisites
+	WatchIgnore     []string `json:"watch_ignore,omitempty"`      // replaces the default ignore list (.git, dist, build, ...)
+	WatchIntervalMs int      `json:"watch_interval_ms,omitempty"` // polling interval
+	WatchDebounceMs int      `json:"watch_debounce_ms,omitempty"` // quiet period before re-running
+
+	// Run logs
+	LogKeep       int `json:"log_keep,omitempty"`         // log files kept per target (default 10)
+	LogMaxAgeDays int `json:"log_max_age_days,omitempty"` // logs older than this are removed (default 30)
+
+	// History retention
+	HistoryMaxEntries int `json:"history_max_entries,omitempty"`  // runs kept (default 10000)
+	HistoryMaxAgeDays int `json:"history_max_age_days,omitempty"` // older runs are dropped (default 365)
+	HistoryMaxSizeMB  int `json:"history_max_size_mb,omitempty"`  // size of the history file (default 10)
+
+	// History privacy (path.Match patterns)
+	HistoryExcludeTargets []string `json:"history_exclude_targets,omitempty"` // targets never recorded
+	HistoryExcludeDirs    []string `json:"history_exclude_dirs,omitempty"`    // directories never recorded, with subdirectories
+	HistoryRedactArgs     []string `json:"history_redact_args,omitempty"`     // variables (VAR=value) whose value is not stored
+
+	// Notifications for long runs
+	NotifyAfterSeconds int    `json:"notify_after_seconds,omitempty"` // minimum run duration announced (default 30)
+	NotifyBell         bool   `json:"notify_bell"`                    // ring the terminal bell (default true)
+	NotifyOSC          string `json:"notify_osc,omitempty"`           // desktop notification escape: "9", "777", "both" (default) or "off"
+	NotifyCommand      string `json:"notify_command,omitempty"`       // e.g. "notify-send mk {target} {status} {duration}"
+
+	// Project settings, usually set in a project's .mk.json
+	Makefile       string            `json:"makefile,omitempty"`        // used instead of searching the current directory
+	MakefileNames  []string          `json:"makefile_names,omitempty"`  // names searched, in order (default Makefile, makefile, GNUmakefile)
+	DocumentedOnly bool              `json:"documented_only,omitempty"` // only list targets with a ## description
+	HiddenTargets  []string          `json:"hidden_targets,omitempty"`  // path.Match patterns of targets left out of menus
+	TargetOrder    []string          `json:"target_order,omitempty"`    // targets listed first, in this order
+	Aliases        map[string]string `json:"aliases,omitempty"`         // name -> "target [VAR=value...]"
+	DefaultVars    map[string]string `json:"default_vars,omitempty"`    // VAR=value passed to every make run
 }
 
-// Manager handles persistent configuration.
+// Manager handles persistent configuration. Saves are made under a file
+// lock and only write the settings changed through this Manager, so that two
+// mk processes changing different settings do not undo each other.
+//
+// Config is the effective configuration: the user's config.json layered
+// with the .mk.json files of the current project and the MK_* environment
+// variables, which take precedence. Only the user file is ever written.
 type Manager struct {
-	filePath string
-	Config   Config
+	filePath      string
+	Config        Config
+	user          map[string]json.RawMessage // user file content when last read or written
+	projects      []layer                    // from the repository root down to the current directory
+	env           []layer                    // one per environment variable set
+	loaded        map[string]json.RawMessage // effective settings when last applied
+	sources       map[string]string          // file or variable each setting of loaded comes from
+	recovered     *safefile.CorruptError
+	warnings      []error
+	schemeWarning error // color_scheme naming no theme, found by apply
 }
 
-// New creates a new configuration Manager.
+// New creates a new configuration Manager. The user file is
+// ~/.config/mk/config.json, or the path set in MK_CONFIG.
 func New() (*Manager, error) {
-	dir, err := os.UserConfigDir()
-	if err != nil {
-		dir = os.TempDir()
+	filePath := os.Getenv("MK_CONFIG")
+	if filePath == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			dir = os.TempDir()
+		}
+		filePath = filepath.Join(dir, "mk", "config.json")
 	}
-
-	configDir := filepath.Join(dir, "mk")
-	if err := os.MkdirAll(configDir, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
 		return nil, err
 	}
 
 	m := &Manager{
-		filePath: filepath.Join(configDir, "config.json"),
-		Config: Config{
-			KeyScheme:   KeySchemeArrows,
-			Language:    i18n.LangEN,
-			ColorScheme: ColorSchemeRainbow,
-		},
+		filePath: filePath,
+		Config:   defaultConfig(),
 	}
 
-	_ = m.load()
+	if err := m.load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		var pathErr *fs.PathError
+		if !errors.As(err, &pathErr) {
+			// Unreadable JSON: start from the defaults, keeping the old file aside.
+			m.recovered = safefile.MoveAside(m.filePath, err)
+		}
+	}
+	if wd, err := os.Getwd(); err == nil {
+		m.loadProjects(wd)
+	}
+	m.loadEnv()
+	m.apply()
 
 	return m, nil
 }
 
+func defaultConfig() Config {
+	return Config{
+		Version:     CurrentVersion,
+		KeyScheme:   KeySchemeArrows,
+		Language:    i18n.LangEN,
+		ColorScheme: ColorSchemeRainbow,
+		NotifyBell:  true,
+		NotifyOSC:   "both",
+	}
+}
+
+// Recovered reports the corrupted configuration file that was moved aside
+// while loading, if any, so that the user can be warned about it.
+func (m *Manager) Recovered() *safefile.CorruptError {
+	return m.recovered
+}
+
+// Warnings reports the files, settings and environment variables that were
+// ignored because they could not be used: unreadable project files, unknown
+// settings and invalid values.
+func (m *Manager) Warnings() []error {
+	if m.schemeWarning != nil {
+		return append(slices.Clip(m.warnings), m.schemeWarning)
+	}
+	return m.warnings
+}
+
 // Exists reports whether the configuration file exists on disk.
 func (m *Manager) Exists() bool {
 	_, err := os.Stat(m.filePath)
 	return err == nil
 }
 
+// load reads the user file, upgrading it to the current schema first. Its
+// valid settings take effect once applied; the others are reported.
 func (m *Manager) load() error {
-	data, err := os.ReadFile(m.filePath)
+	fields, err := readFields(m.filePath)
 	if err != nil {
 		return err
 	}
-	if err := json.Unmarshal(data, &m.Config); err != nil {
-		return err
+	fields = m.upgrade(fields)
+	valid, problems := validate(m.filePath, fields)
+	m.warnings = append(m.warnings, problems...)
+	m.user = valid
+	return nil
+}
+
+// apply computes the effective configuration from the defaults, the user
+// file, the project files and the environment, in increasing order of
+// precedence. A setting is taken as a whole from the last layer that sets it.
+func (m *Manager) apply() {
+	m.loaded = make(map[string]json.RawMessage)
+	m.sources = make(map[string]string)
+	for key, value := range m.user {
+		m.loaded[key] = value
+		m.sources[key] = m.filePath
+	}
+	for _, l := range slices.Concat(m.projects, m.env) {
+		for key, value := range l.fields {
+			m.loaded[key] = value
+			m.sources[key] = l.path
+		}
 	}
+
+	m.Config = defaultConfig()
+	data, _ := json.Marshal(m.loaded)
+	_ = json.Unmarshal(data, &m.Config)
+	normalize(&m.Config)
+	m.checkScheme()
+
+	// A project's Makefile is relative to its .mk.json.
+	if src := m.sources["makefile"]; src != m.filePath && m.Config.Makefile != "" && !filepath.IsAbs(m.Config.Makefile) {
+		m.Config.Makefile = filepath.Join(filepath.Dir(src), m.Config.Makefile)
+		m.loaded["makefile"], _ = json.Marshal(m.Config.Makefile)
+	}
+}
+
+// normalize fills in missing settings.
+func normalize(c *Config) {
 	// Default missing language to EN.
-	if m.Config.Language == "" {
-		m.Config.Language = i18n.LangEN
+	if c.Language == "" {
+		c.Language = i18n.LangEN
 	}
 	// Default missing color scheme to rainbow.
-	if m.Config.ColorScheme == "" {
-		m.Config.ColorScheme = ColorSchemeRainbow
+	if c.ColorScheme == "" {
+		c.ColorScheme = ColorSchemeRainbow
 	}
-	// Migrate legacy "zqsd" key scheme to "custom" with z/s keys.
-	if m.Config.KeyScheme == "zqsd" {
-		m.Config.KeyScheme = KeySchemeCustom
-		m.Config.CustomUpKey = 'z'
-		m.Config.CustomDownKey = 's'
-	}
-	return nil
 }
 
-// Save writes the configuration to disk.
+// Save writes the configuration to disk. The file is read again under lock
+// and only the settings that differ from what this Manager loaded are
+// applied to it; the result is written atomically and becomes m.Config.
 func (m *Manager) Save() error {
-	data, err := json.MarshalIndent(m.Config, "", "  ")
+	current, err := fieldsOf(m.Config)
+	if err != nil {
+		return err
+	}
+	defaults, err := fieldsOf(defaultConfig())
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(m.filePath, data, 0600)
+	return m.update(func(disk map[string]json.RawMessage) {
+		for key, value := range current {
+			// A setting left out of loaded, such as one whose value on disk
+			// is invalid, was loaded as its default.
+			loaded, ok := m.loaded[key]
+			if !ok {
+				loaded = defaults[key]
+			}
+			if !bytes.Equal(loaded, value) {
+				disk[key] = value
+			}
+		}
+		for key := range m.loaded {
+			if _, ok := current[key]; !ok {
+				delete(disk, key) // cleared setting (omitted when empty)
+			}
+		}
+	})
+}
+
+// update applies change to the settings of the user file, read under lock,
+// and writes the result atomically. Invalid values and unknown settings are
+// written back as they are, for the user to fix or for the version of mk that
+// knows them, but only valid values take effect. The effective configuration
+// is then computed again.
+func (m *Manager) update(change func(disk map[string]json.RawMessage)) error {
+	unlock, err := safefile.Lock(m.filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	disk, err := readFields(m.filePath)
+	var pathErr *fs.PathError
+	switch {
+	case err == nil:
+	case errors.Is(err, os.ErrNotExist):
+		disk = map[string]json.RawMessage{}
+	case errors.As(err, &pathErr):
+		return err
+	default:
+		m.recovered = safefile.MoveAside(m.filePath, err)
+		disk = map[string]json.RawMessage{}
+	}
+	migrate(disk)
+	change(disk)
+
+	valid, _ := validate(m.filePath, disk)
+	merged := defaultConfig()
+	data, _ := json.Marshal(valid)
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return err
+	}
+	normalize(&merged)
+
+	fields, err := fieldsOf(merged)
+	if err != nil {
+		return err
+	}
+	m.user = maps.Clone(fields)
+	for key, value := range disk {
+		if _, ok := valid[key]; !ok {
+			fields[key] = value
+		}
+	}
+	data, err = encodeFields(fields)
+	if err != nil {
+		return err
+	}
+	if err := safefile.WriteFile(m.filePath, data, 0600); err != nil {
+		return err
+	}
+	m.apply()
+	return nil
+}
+
+// readFields reads a JSON object file as compacted raw values per key.
+func readFields(path string) (map[string]json.RawMessage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		fields = map[string]json.RawMessage{}
+	}
+	for key, value := range fields {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, value); err == nil {
+			fields[key] = buf.Bytes()
+		}
+	}
+	return fields, nil
+}
+
+// fieldsOf returns the JSON encoding of c per key.
+func fieldsOf(c Config) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(data, &fields)
+	return fields, err
 }
 
 // SetupResult holds the result of the interactive setup.
@@ -121,19 +382,31 @@ type SetupResult struct {
 	CustomDownKey byte
 }
 
-// RunSetup runs the full interactive setup (language, colors, keys).
-func RunSetup() (SetupResult, error) {
+// RunSetup runs the full interactive setup (language, colors, keys): as
+// menus with opts.Select when the terminal can show them, as numbered
+// questions otherwise.
+func RunSetup(opts SetupOptions) (SetupResult, error) {
 	result := SetupResult{}
 
 	fmt.Printf("%s%s⚙  mk — setup%s\n\n", ansi.Bold, ansi.Purple, ansi.Reset)
 
+	err := runSteps(opts, selectLang(&result.Language, opts), selectColor(&result.ColorScheme, opts), selectKeys(&result.KeyScheme, opts))
+	if !errors.Is(err, ErrSelectUnavailable) {
+		if err == nil {
+			confirmLang()
+			confirmColor(result.ColorScheme)
+			confirmKeys(result.KeyScheme)
+		}
+		return result, err
+	}
+
 	lang, err := promptLang()
 	if err != nil {
 		return result, err
 	}
 	result.Language = lang
 
-	cs, err := promptColor()
+	cs, err := promptColor(opts)
 	if err != nil {
 		return result, err
 	}
@@ -149,20 +422,41 @@ func RunSetup() (SetupResult, error) {
 }
 
 // RunLangSetup prompts the user to change the language.
-func RunLangSetup() (i18n.Lang, error) {
+func RunLangSetup(opts SetupOptions) (i18n.Lang, error) {
 	fmt.Printf("%s%s⚙  mk — language%s\n\n", ansi.Bold, ansi.Purple, ansi.Reset)
+	var lang i18n.Lang
+	if err := runSteps(opts, selectLang(&lang, opts)); !errors.Is(err, ErrSelectUnavailable) {
+		if err == nil {
+			confirmLang()
+		}
+		return lang, err
+	}
 	return promptLang()
 }
 
 // RunColorSetup prompts the user to change the color scheme.
-func RunColorSetup() (ColorScheme, error) {
+func RunColorSetup(opts SetupOptions) (ColorScheme, error) {
 	fmt.Printf("%s%s⚙  mk — colors%s\n\n", ansi.Bold, ansi.Purple, ansi.Reset)
-	return promptColor()
+	var cs ColorScheme
+	if err := runSteps(opts, selectColor(&cs, opts)); !errors.Is(err, ErrSelectUnavailable) {
+		if err == nil {
+			confirmColor(cs)
+		}
+		return cs, err
+	}
+	return promptColor(opts)
 }
 
 // RunKeysSetup prompts the user to change the key scheme.
-func RunKeysSetup() (KeyScheme, error) {
+func RunKeysSetup(opts SetupOptions) (KeyScheme, error) {
 	fmt.Printf("%s%s⚙  mk — keys%s\n\n", ansi.Bold, ansi.Purple, ansi.Reset)
+	var ks KeyScheme
+	if err := runSteps(opts, selectKeys(&ks, opts)); !errors.Is(err, ErrSelectUnavailable) {
+		if err == nil {
+			confirmKeys(ks)
+		}
+		return ks, err
+	}
 	return promptKeys()
 }
 
@@ -202,7 +496,7 @@ func promptLang() (i18n.Lang, error) {
 	}
 }
 
-func promptColor() (ColorScheme, error) {
+func promptColor(opts SetupOptions) (ColorScheme, error) {
 	reader := bufio.NewReader(os.Stdin)
 	m := i18n.Get()
 
@@ -210,8 +504,9 @@ func promptColor() (ColorScheme, error) {
 	fmt.Printf("  %s1.%s %s %s%s%s\n", ansi.Purple, ansi.Reset, m.ConfigColorRainbow, ansi.Gray, m.ConfigColorRainbowHint, ansi.Reset)
 	fmt.Printf("  %s2.%s %s %s%s%s\n", ansi.Purple, ansi.Reset, m.ConfigColorDeuteranopia, ansi.Gray, m.ConfigColorDeuteranopiaHint, ansi.Reset)
 	fmt.Printf("  %s3.%s %s %s%s%s\n", ansi.Purple, ansi.Reset, m.ConfigColorTritanopia, ansi.Gray, m.ConfigColorTritanopiaHint, ansi.Reset)
-	fmt.Printf("  %s4.%s %s %s%s%s\n", ansi.Purple, ansi.Reset, m.ConfigColorHighContrast, ansi.Gray, m.ConfigColorHighContrastHint, ansi.Reset)
-	fmt.Printf("\n%s%s%s", ansi.Gray, m.ConfigColorChoice, ansi.Reset)
+	fmt.Printf("  %s4.%s %s %s%s%s\n\n", ansi.Purple, ansi.Reset, m.ConfigColorHighContrast, ansi.Gray, m.ConfigColorHighContrastHint, ansi.Reset)
+	printColorPreview(opts)
+	fmt.Printf("%s%s%s", ansi.Gray, m.ConfigColorChoice, ansi.Reset)
 
 	for {
 		input, _ := reader.ReadString('\n')
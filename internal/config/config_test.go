@@ -154,3 +154,205 @@ func TestExists(t *testing.T) {
 		t.Error("config should exist after Save")
 	}
 }
+
+func TestSaveKeepsConcurrentChanges(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	a, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two processes load the same file, then each changes a different setting.
+	b, _ := New()
+	c, _ := New()
+	b.Config.Language = i18n.LangDE
+	if err := b.Save(); err != nil {
+		t.Fatal(err)
+	}
+	c.Config.ColorScheme = ColorSchemeTritanopia
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	d, _ := New()
+	if d.Config.Language != i18n.LangDE {
+		t.Errorf("language change lost: got %q", d.Config.Language)
+	}
+	if d.Config.ColorScheme != ColorSchemeTritanopia {
+		t.Errorf("color change lost: got %q", d.Config.ColorScheme)
+	}
+	if c.Config.Language != i18n.LangDE {
+		t.Errorf("Save should refresh the in-memory config, got language %q", c.Config.Language)
+	}
+}
+
+func TestCorruptedConfigMovedAside(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	configDir := filepath.Join(dir, "mk")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(configDir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"language": "fr",`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := cfg.Recovered()
+	if rec == nil || rec.Backup == "" {
+		t.Fatal("expected the corrupted file to be moved aside")
+	}
+	if cfg.Config.Language != i18n.LangEN {
+		t.Errorf("expected defaults, got language %q", cfg.Config.Language)
+	}
+	if cfg.Exists() {
+		t.Error("the corrupted file should no longer be in place")
+	}
+	if data, _ := os.ReadFile(rec.Backup); string(data) != `{"language": "fr",` {
+		t.Errorf("backup content lost: %q", data)
+	}
+}
+
+func TestProjectLayers(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	repo := t.TempDir()
+	sub := filepath.Join(repo, "service")
+	for _, dir := range []string{filepath.Join(repo, ".git"), sub} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(repo, ProjectFile), `{"menu_order": "frecency", "aliases": {"t": "test"}, "color_scheme": "tritanopia"}`)
+	write(filepath.Join(sub, ProjectFile), `{"makefile": "build/Makefile", "aliases": {"b": "build"}}`)
+	t.Chdir(sub)
+
+	user, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.Config.ColorScheme = ColorSchemeHighContrast
+	user.Config.Language = i18n.LangDE
+	if err := user.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// User settings in a project file are ignored with a warning.
+	if len(cfg.Warnings()) != 2 {
+		t.Fatal(cfg.Warnings())
+	}
+	c := cfg.Config
+	if c.Language != i18n.LangDE || c.ColorScheme != ColorSchemeHighContrast || c.MenuOrder != "" {
+		t.Errorf("unexpected layering: %+v", c)
+	}
+	if len(c.Aliases) != 1 || c.Aliases["b"] != "build" {
+		t.Errorf("expected the closest aliases to win as a whole, got %v", c.Aliases)
+	}
+	if want := filepath.Join(sub, "build", "Makefile"); c.Makefile != want {
+		t.Errorf("expected makefile %q, got %q", want, c.Makefile)
+	}
+
+	sources := map[string]string{}
+	for _, s := range cfg.Settings() {
+		sources[s.Key] = s.Source
+	}
+	if sources["language"] != cfg.Path() || sources["color_scheme"] != cfg.Path() ||
+		sources["aliases"] != filepath.Join(sub, ProjectFile) || sources["key_scheme"] != cfg.Path() {
+		t.Errorf("unexpected sources %v", sources)
+	}
+
+	// Saving must not copy project settings into the user file.
+	cfg.Config.Language = i18n.LangFR
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(cfg.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["color_scheme"] != string(ColorSchemeHighContrast) || fields["language"] != "fr" || fields["aliases"] != nil || fields["makefile"] != nil {
+		t.Errorf("project settings leaked into the user file: %s", data)
+	}
+}
+
+func TestInvalidProjectFileIgnored(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ProjectFile), []byte(`{"menu_order": 3`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	cfg, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Warnings()) != 1 {
+		t.Error("expected an error for the invalid project file")
+	}
+	if cfg.Config.MenuOrder != "" {
+		t.Errorf("expected the project file to be ignored, got menu_order %q", cfg.Config.MenuOrder)
+	}
+}
+
+func TestEnvironmentOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom", "mk.json")
+	t.Setenv("MK_CONFIG", path)
+	t.Setenv("MK_LANG", "de")
+	t.Setenv("MK_COLORS", "sepia")
+	t.Setenv("MK_KEYS", "")
+
+	cfg, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Path() != path {
+		t.Errorf("expected config file %q, got %q", path, cfg.Path())
+	}
+	if cfg.Config.Language != i18n.LangDE {
+		t.Errorf("expected MK_LANG to set the language, got %q", cfg.Config.Language)
+	}
+	if cfg.Config.ColorScheme != ColorSchemeRainbow || len(cfg.Warnings()) != 1 {
+		t.Errorf("expected an invalid MK_COLORS to be ignored with a warning, got %q and %v", cfg.Config.ColorScheme, cfg.Warnings())
+	}
+	for _, s := range cfg.Settings() {
+		if s.Key == "language" && s.Source != "$MK_LANG" {
+			t.Errorf("expected language to come from $MK_LANG, got %q", s.Source)
+		}
+	}
+
+	// Overrides are not saved.
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("MK_LANG", "")
+	cfg2, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg2.Config.Language != i18n.LangEN {
+		t.Errorf("expected the saved language to stay en, got %q", cfg2.Config.Language)
+	}
+}
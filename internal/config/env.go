@@ -0,0 +1,58 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/subut0n/mk/internal/i18n"
+)
+
+// envOverrides lists the environment variables that override a setting.
+var envOverrides = []struct {
+	name, key string
+	values    func() []string
+}{
+	{"MK_LANG", "language", func() []string { return names(i18n.SupportedLangs()) }},
+	{"MK_COLORS", "color_scheme", nil},
+	{"MK_KEYS", "key_scheme", func() []string { return names(KeySchemes()) }},
+}
+
+func names[T ~string](values []T) []string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = string(v)
+	}
+	return s
+}
+
+// loadEnv reads the settings overridden by environment variables. An
+// unknown value is ignored with a warning; the color scheme is checked by
+// apply, once the themes are known.
+func (m *Manager) loadEnv() {
+	m.env = nil
+	for _, o := range envOverrides {
+		value := os.Getenv(o.name)
+		if value == "" {
+			continue
+		}
+		if o.values != nil && !slices.Contains(o.values(), value) {
+			m.warnings = append(m.warnings, fmt.Errorf("%s=%s: expected one of %s", o.name, value, strings.Join(o.values(), ", ")))
+			continue
+		}
+		data, _ := json.Marshal(value)
+		m.env = append(m.env, layer{path: "$" + o.name, fields: map[string]json.RawMessage{o.key: data}})
+	}
+}
+
+// KeySchemes returns all valid key schemes.
+func KeySchemes() []KeyScheme {
+	return []KeyScheme{KeySchemeArrows, KeySchemeWASD, KeySchemeCustom}
+}
+
+// ColorSchemes returns the built-in color schemes.
+func ColorSchemes() []ColorScheme {
+	return []ColorScheme{ColorSchemeRainbow, ColorSchemeDeuteranopia, ColorSchemeTritanopia, ColorSchemeHighContrast}
+}
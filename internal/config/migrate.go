@@ -0,0 +1,180 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/subut0n/mk/internal/safefile"
+	"github.com/subut0n/mk/internal/theme"
+)
+
+// CurrentVersion is the version of the configuration schema written by this
+// build of mk. Files of an earlier version are upgraded by the migrations
+// when they are loaded.
+const CurrentVersion = 1
+
+// migrations[i] upgrades the settings of a file from version i to i+1. A
+// migration is never modified once released: changes to the schema add a
+// new one at the end and increase CurrentVersion.
+var migrations = []func(fields map[string]json.RawMessage){
+	migrateZQSD, // 0 → 1
+}
+
+// migrateZQSD turns the "zqsd" key scheme of early versions, the AZERTY
+// counterpart of WASD, into a custom scheme with the same keys.
+func migrateZQSD(fields map[string]json.RawMessage) {
+	if string(fields["key_scheme"]) == `"zqsd"` {
+		fields["key_scheme"] = json.RawMessage(`"custom"`)
+		fields["custom_up_key"] = json.RawMessage(strconv.Itoa('z'))
+		fields["custom_down_key"] = json.RawMessage(strconv.Itoa('s'))
+	}
+}
+
+// fileVersion returns the schema version of a configuration file, 0 for the
+// files written before versions were introduced.
+func fileVersion(fields map[string]json.RawMessage) int {
+	var version int
+	_ = json.Unmarshal(fields["version"], &version)
+	return version
+}
+
+// migrate applies the migrations from the version of fields to
+// CurrentVersion and reports whether any was needed.
+func migrate(fields map[string]json.RawMessage) bool {
+	version := fileVersion(fields)
+	if version >= CurrentVersion {
+		return false
+	}
+	for _, m := range migrations[max(version, 0):] {
+		m(fields)
+	}
+	fields["version"] = json.RawMessage(strconv.Itoa(CurrentVersion))
+	return true
+}
+
+// upgrade migrates the user file to CurrentVersion under lock. The previous
+// content is kept in config.json.v<N>.bak, N being its version. When the
+// file cannot be written, the migrated settings are only used in memory.
+func (m *Manager) upgrade(fields map[string]json.RawMessage) map[string]json.RawMessage {
+	if fileVersion(fields) >= CurrentVersion {
+		return fields
+	}
+	unlock, err := safefile.Lock(m.filePath)
+	if err != nil {
+		migrate(fields)
+		return fields
+	}
+	defer unlock()
+
+	// Another process may have upgraded the file in the meantime.
+	data, err := os.ReadFile(m.filePath)
+	if err != nil {
+		migrate(fields)
+		return fields
+	}
+	if current, err := readFields(m.filePath); err == nil {
+		fields = current
+	}
+	version := fileVersion(fields)
+	if !migrate(fields) {
+		return fields
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", m.filePath, version)
+	if err := safefile.WriteFile(backup, data, 0600); err != nil {
+		return fields
+	}
+	if data, err := encodeFields(fields); err == nil {
+		_ = safefile.WriteFile(m.filePath, data, 0600)
+	}
+	return fields
+}
+
+// validate returns the settings of fields that mk can use. Unknown settings
+// and invalid values are left out and reported, prefixed with path; unknown
+// settings are not reported in files of a newer schema, which may add some.
+// Invalid theme colors are reported too, but their themes are kept.
+func validate(path string, fields map[string]json.RawMessage) (map[string]json.RawMessage, []error) {
+	valid := make(map[string]json.RawMessage)
+	var problems []error
+	newer := fileVersion(fields) > CurrentVersion
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		raw := fields[key]
+		f, ok := field(key)
+		if !ok {
+			if !newer {
+				problems = append(problems, fmt.Errorf("%s: %w", path, unknownKey(key)))
+			}
+			continue
+		}
+		value := reflect.New(f.Type)
+		if err := json.Unmarshal(raw, value.Interface()); err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", path, &InvalidValueError{Key: key, Value: string(raw), Err: err}))
+			continue
+		}
+		if allowed := allowedValues(key); allowed != nil && value.Elem().Kind() == reflect.String {
+			if s := value.Elem().String(); s != "" && !slices.Contains(allowed, s) {
+				problems = append(problems, fmt.Errorf("%s: %w", path, &InvalidValueError{Key: key, Value: s, Allowed: allowed}))
+				continue
+			}
+		}
+		// Themes are kept despite their invalid colors, each of which
+		// falls back to its default when the theme is applied.
+		if themes, ok := value.Interface().(*map[string]theme.Theme); ok {
+			for _, err := range themeProblems(*themes) {
+				problems = append(problems, fmt.Errorf("%s: %w", path, err))
+			}
+		}
+		valid[key] = raw
+	}
+	if newer {
+		problems = append(problems, fmt.Errorf("%s: written by a newer version of mk (schema %d, this version reads %d)", path, fileVersion(fields), CurrentVersion))
+	}
+	return valid, problems
+}
+
+// encodeFields encodes the settings of a configuration file as indented
+// JSON, in the order of the Config fields followed by unknown settings.
+func encodeFields(fields map[string]json.RawMessage) ([]byte, error) {
+	keys := slices.DeleteFunc(Keys(), func(key string) bool {
+		_, ok := fields[key]
+		return !ok
+	})
+	var unknown []string
+	for key := range fields {
+		if !slices.Contains(keys, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, key := range append(keys, unknown...) {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		name, _ := json.Marshal(key)
+		buf.WriteString("\n  ")
+		buf.Write(name)
+		buf.WriteString(": ")
+		if err := json.Indent(&buf, fields[key], "  ", "  "); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteString("\n}\n")
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/subut0n/mk/internal/i18n"
+)
+
+// writeUserConfig writes content as the user file of a fresh configuration
+// directory and returns its path.
+func writeUserConfig(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Chdir(t.TempDir())
+	path := filepath.Join(dir, "mk", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMigrationBackup(t *testing.T) {
+	legacy := `{"key_scheme": "zqsd", "language": "de"}`
+	path := writeUserConfig(t, legacy)
+
+	cfg, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Warnings()) != 0 {
+		t.Fatal(cfg.Warnings())
+	}
+	if data, err := os.ReadFile(path + ".v0.bak"); err != nil || string(data) != legacy {
+		t.Errorf("expected the previous file in the backup, got %q (%v)", data, err)
+	}
+
+	var fields map[string]any
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["version"] != float64(CurrentVersion) || fields["key_scheme"] != "custom" || fields["language"] != "de" {
+		t.Errorf("unexpected migrated file: %s", data)
+	}
+
+	// An up-to-date file is left alone.
+	if err := os.Remove(path + ".v0.bak"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".v0.bak"); err == nil {
+		t.Error("an up-to-date file should not be backed up again")
+	}
+}
+
+func TestValidationWarnings(t *testing.T) {
+	path := writeUserConfig(t, `{"version": 1, "languge": "fr", "menu_order": "alphabetical", "log_keep": "ten", "color_scheme": "tritanopia"}`)
+
+	cfg, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Recovered() != nil {
+		t.Fatal("a file with invalid settings should not be treated as corrupted")
+	}
+	if cfg.Config.ColorScheme != ColorSchemeTritanopia {
+		t.Errorf("valid settings should be kept, got color_scheme %q", cfg.Config.ColorScheme)
+	}
+	if cfg.Config.MenuOrder != "" || cfg.Config.LogKeep != 0 || cfg.Config.Language != i18n.LangEN {
+		t.Errorf("invalid settings should be ignored: %+v", cfg.Config)
+	}
+
+	warnings := cfg.Warnings()
+	if len(warnings) != 3 {
+		t.Fatalf("expected 3 warnings, got %v", warnings)
+	}
+	var unknown *UnknownKeyError
+	if !errors.As(warnings[0], &unknown) || unknown.Key != "languge" || unknown.Suggestion != "language" {
+		t.Errorf("expected an unknown setting with a suggestion, got %v", warnings[0])
+	}
+	var invalid *InvalidValueError
+	if !errors.As(warnings[1], &invalid) || invalid.Key != "log_keep" {
+		t.Errorf("expected an invalid log_keep, got %v", warnings[1])
+	}
+	if !errors.As(warnings[2], &invalid) || invalid.Key != "menu_order" || len(invalid.Allowed) == 0 {
+		t.Errorf("expected an invalid menu_order, got %v", warnings[2])
+	}
+
+	// Saving an unrelated setting leaves the invalid values for the user to fix.
+	if err := cfg.Set("language", "de"); err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["menu_order"] != "alphabetical" || fields["log_keep"] != "ten" || fields["language"] != "de" {
+		t.Errorf("expected the invalid values to stay on disk, got %s", data)
+	}
+	if cfg.Config.MenuOrder != "" || cfg.Config.LogKeep != 0 {
+		t.Errorf("invalid settings should still be ignored: %+v", cfg.Config)
+	}
+}
+
+func TestNewerVersionKept(t *testing.T) {
+	path := writeUserConfig(t, `{"version": 99, "language": "fr", "future_setting": {"on": true}}`)
+
+	cfg, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Config.Language != i18n.LangFR {
+		t.Errorf("expected known settings to be read, got %q", cfg.Config.Language)
+	}
+	if err := cfg.Set("menu_order", "recent"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	fields, err := readFields(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(fields["version"]) != "99" || string(fields["future_setting"]) != `{"on":true}` || string(fields["menu_order"]) != `"recent"` {
+		t.Errorf("settings of the newer version lost on save: %s", data)
+	}
+	if _, err := os.Stat(path + ".v99.bak"); err == nil {
+		t.Error("a newer file should not be migrated")
+	}
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/subut0n/mk/internal/ansi"
+	"github.com/subut0n/mk/internal/i18n"
+	"github.com/subut0n/mk/internal/theme"
+)
+
+// SetupOptions tell the setup how to ask its questions and how color
+// schemes look on this terminal.
+type SetupOptions struct {
+	// Select asks a question in an interactive menu and returns the index
+	// of the choice. mk sets it to the menus of the ui package, which
+	// depends on this one; without it the setup asks numbered questions.
+	Select func(SetupStep) (int, error)
+	// Theme returns the theme of a color scheme, resolved for the
+	// background of the terminal.
+	Theme func(ColorScheme) theme.Theme
+	// Depth is the number of colors of the terminal. Below 256 colors the
+	// color vision simulations are left out: snapped to the 16 ANSI colors,
+	// they would not show what the deficiencies do.
+	Depth theme.Depth
+}
+
+// previewTargets are the sample targets of the preview menus.
+var previewTargets = []string{"build", "test", "lint", "deploy", "clean", "docs"}
+
+const (
+	previewLabelWidth  = 14
+	previewColumnWidth = 15
+)
+
+// printColorPreview prints the lines of colorPreviewLines and a blank line.
+func printColorPreview(opts SetupOptions) {
+	if lines := colorPreviewLines(opts); lines != nil {
+		fmt.Println(strings.Join(lines, "\n"))
+		fmt.Println()
+	}
+}
+
+// colorPreviewLines shows a sample menu for each built-in scheme side by
+// side, numbered as in the list of schemes, and below them the palettes as
+// they appear with each color vision deficiency, on terminals of at least
+// 256 colors. Without colors there is nothing to show.
+func colorPreviewLines(opts SetupOptions) []string {
+	if !ansi.ColorsEnabled() || opts.Theme == nil {
+		return nil
+	}
+	m := i18n.Get()
+	var lines []string
+	schemes := ColorSchemes()
+
+	var b strings.Builder
+	b.WriteString("  " + pad("", previewLabelWidth))
+	for i := range schemes {
+		b.WriteString(pad(fmt.Sprintf("  %s%s%d%s", ansi.Bold, ansi.Purple, i+1, ansi.Reset), previewColumnWidth))
+	}
+	lines = append(lines, strings.TrimRight(b.String(), " "))
+
+	palettes := make([][]string, len(schemes))
+	for i, cs := range schemes {
+		palettes[i] = opts.Theme(cs).PaletteCodes(opts.Depth)
+	}
+	for row, name := range previewTargets {
+		b.Reset()
+		b.WriteString("  " + pad("", previewLabelWidth))
+		for _, palette := range palettes {
+			c := palette[row%len(palette)]
+			if row == 0 {
+				b.WriteString(pad(fmt.Sprintf("%s%s▶%s %s%s%s%s", ansi.Bold, ansi.Cursor, ansi.Reset, ansi.Bold, c, name, ansi.Reset), previewColumnWidth))
+			} else {
+				b.WriteString(pad(fmt.Sprintf("  %s%s%s", c, name, ansi.Reset), previewColumnWidth))
+			}
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+	}
+
+	if opts.Depth < theme.Depth256 {
+		return lines
+	}
+	lines = append(lines, "", fmt.Sprintf("  %s%s%s", ansi.Gray, m.ConfigColorPreview, ansi.Reset))
+	rows := []struct {
+		label  string
+		vision theme.Vision
+		normal bool
+	}{
+		{m.ConfigVisionNormal, 0, true},
+		{m.ConfigVisionDeuteranopia, theme.Deuteranopia, false},
+		{m.ConfigVisionProtanopia, theme.Protanopia, false},
+		{m.ConfigVisionTritanopia, theme.Tritanopia, false},
+	}
+	for _, r := range rows {
+		b.Reset()
+		b.WriteString("  " + pad(r.label, previewLabelWidth))
+		for i, cs := range schemes {
+			codes := palettes[i]
+			if !r.normal {
+				codes = opts.Theme(cs).SimulatedCodes(r.vision, opts.Depth)
+			}
+			var swatches strings.Builder
+			for _, c := range codes {
+				swatches.WriteString(c + "██" + ansi.Reset)
+			}
+			b.WriteString(pad("  "+swatches.String(), previewColumnWidth))
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+	}
+	return lines
+}
+
+// pad right-pads s with spaces to width columns, ignoring escape sequences.
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-visibleWidth(s), 0))
+}
+
+// visibleWidth returns the number of columns s takes on screen.
+func visibleWidth(s string) int {
+	n := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\033' {
+			if end := strings.IndexByte(s[i:], 'm'); end >= 0 {
+				i += end + 1
+				continue
+			}
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		n++
+	}
+	return n
+}
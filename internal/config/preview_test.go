@@ -0,0 +1,22 @@
+package config
+
+import "testing"
+
+func TestPad(t *testing.T) {
+	cases := []struct {
+		s    string
+		want int
+	}{
+		{"build", 5},
+		{"\033[1m\033[38;5;208m▶\033[0m build", 7},
+		{"Deutéranopie", 12},
+	}
+	for _, c := range cases {
+		if got := visibleWidth(c.s); got != c.want {
+			t.Errorf("visibleWidth(%q) = %d, want %d", c.s, got, c.want)
+		}
+		if got := visibleWidth(pad(c.s, 10)); got != max(10, c.want) {
+			t.Errorf("pad(%q, 10) is %d columns wide", c.s, got)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ProjectFile is the name of the per-project configuration file, looked up
+// in the current directory and at the root of its git repository.
+const ProjectFile = ".mk.json"
+
+// projectKeys are the settings a project file can set. The others belong to
+// the user: a cloned repository must not change the language, run its own
+// notify_command or trim the history.
+var projectKeys = []string{
+	"makefile",
+	"makefile_names",
+	"documented_only",
+	"hidden_targets",
+	"target_order",
+	"aliases",
+	"default_vars",
+}
+
+// layer is the content of a project file or an environment variable.
+type layer struct {
+	path   string
+	fields map[string]json.RawMessage
+}
+
+// Setting is an effective configuration value and the file it comes from.
+type Setting struct {
+	Key    string
+	Value  json.RawMessage
+	Source string // file path or $VARIABLE, empty for a default value
+}
+
+// projectFiles returns the project files that apply in dir, from the root of
+// its git repository down to dir.
+func projectFiles(dir string) []string {
+	var files []string
+	if _, err := os.Stat(filepath.Join(dir, ProjectFile)); err == nil {
+		files = append(files, filepath.Join(dir, ProjectFile))
+	}
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			if d != dir {
+				if _, err := os.Stat(filepath.Join(d, ProjectFile)); err == nil {
+					files = append([]string{filepath.Join(d, ProjectFile)}, files...)
+				}
+			}
+			break
+		}
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	return files
+}
+
+// loadProjects reads the project files that apply in dir. A file that
+// cannot be read or parsed is skipped with a warning, as are its unknown
+// settings, invalid values and user settings; it is never modified.
+func (m *Manager) loadProjects(dir string) {
+	m.projects = nil
+	for _, path := range projectFiles(dir) {
+		fields, err := readFields(path)
+		if err != nil {
+			m.warnings = append(m.warnings, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		// Project files are shared: they are upgraded in memory only.
+		migrate(fields)
+		fields, problems := validate(path, fields)
+		m.warnings = append(m.warnings, problems...)
+		delete(fields, "version")
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !slices.Contains(projectKeys, key) {
+				m.warnings = append(m.warnings, fmt.Errorf("%s: %s is a user setting and cannot be set by a project", path, key))
+				delete(fields, key)
+			}
+		}
+		m.projects = append(m.projects, layer{path: path, fields: fields})
+	}
+}
+
+// ProjectFiles returns the project files in effect, in increasing order of
+// precedence.
+func (m *Manager) ProjectFiles() []string {
+	var files []string
+	for _, l := range m.projects {
+		files = append(files, l.path)
+	}
+	return files
+}
+
+// Path returns the path of the user configuration file.
+func (m *Manager) Path() string {
+	return m.filePath
+}
+
+// Settings returns the effective settings that have a value, in the order
+// of the Config fields, with the file each one comes from.
+func (m *Manager) Settings() []Setting {
+	current, _ := fieldsOf(m.Config)
+	var settings []Setting
+	for _, key := range Keys() {
+		value, ok := current[key]
+		if !ok {
+			continue
+		}
+		settings = append(settings, Setting{Key: key, Value: value, Source: m.sources[key]})
+	}
+	return settings
+}
+
+// Keys returns the JSON keys of the Config fields, in declaration order.
+func Keys() []string {
+	t := reflect.TypeFor[Config]()
+	keys := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		keys = append(keys, name)
+	}
+	return keys
+}
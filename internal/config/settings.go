@@ -0,0 +1,285 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/subut0n/mk/internal/i18n"
+	"github.com/subut0n/mk/internal/theme"
+)
+
+// UnknownKeyError reports a setting name that does not exist.
+type UnknownKeyError struct {
+	Key        string
+	Suggestion string // closest existing setting, if any
+}
+
+func (e *UnknownKeyError) Error() string {
+	if e.Suggestion != "" {
+		return fmt.Sprintf("unknown setting %q (did you mean %s?)", e.Key, e.Suggestion)
+	}
+	return fmt.Sprintf("unknown setting %q", e.Key)
+}
+
+// InvalidValueError reports a value that a setting does not accept.
+type InvalidValueError struct {
+	Key, Value string
+	Allowed    []string // accepted values, when they are a fixed set
+	Err        error    // parse error otherwise
+}
+
+func (e *InvalidValueError) Error() string {
+	if len(e.Allowed) > 0 {
+		return fmt.Sprintf("invalid value %q for %s (expected one of %s)", e.Value, e.Key, strings.Join(e.Allowed, ", "))
+	}
+	return fmt.Sprintf("invalid value %q for %s: %v", e.Value, e.Key, e.Err)
+}
+
+func (e *InvalidValueError) Unwrap() error {
+	return e.Err
+}
+
+// allowedValues lists the accepted values of the settings that take one of a
+// fixed set. The color schemes depend on the themes defined, see
+// ColorSchemeNames.
+func allowedValues(key string) []string {
+	switch key {
+	case "key_scheme":
+		return names(KeySchemes())
+	case "language":
+		return names(i18n.SupportedLangs())
+	case "menu_order":
+		return names(MenuOrders())
+	case "background":
+		return names([]Background{BackgroundAuto, BackgroundDark, BackgroundLight})
+	case "notify_osc":
+		return []string{"9", "777", "both", "off"}
+	}
+	return nil
+}
+
+// MenuOrders returns all valid menu orders.
+func MenuOrders() []MenuOrder {
+	return []MenuOrder{MenuOrderMakefile, MenuOrderFrecency, MenuOrderRecent}
+}
+
+// field returns the Config field stored under key.
+func field(key string) (reflect.StructField, bool) {
+	t := reflect.TypeFor[Config]()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name == key {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// Get returns the effective value of a setting, as text for strings and
+// numbers and as JSON for lists and maps.
+func (m *Manager) Get(key string) (string, error) {
+	f, ok := field(key)
+	if !ok {
+		return "", unknownKey(key)
+	}
+	v := reflect.ValueOf(m.Config).FieldByIndex(f.Index)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Uint8:
+		if v.Uint() == 0 {
+			return "", nil
+		}
+		return string(rune(v.Uint())), nil
+	case reflect.Slice, reflect.Map:
+		if v.Len() == 0 {
+			return "", nil
+		}
+	}
+	data, err := json.Marshal(v.Interface())
+	return string(data), err
+}
+
+// Set validates value and saves it as the user's setting for key. Lists are
+// given as JSON arrays or comma-separated values, maps as JSON objects or
+// comma-separated name=value pairs, and keys as a single character.
+func (m *Manager) Set(key, value string) error {
+	f, ok := field(key)
+	if !ok {
+		return unknownKey(key)
+	}
+	if key == "version" {
+		return &InvalidValueError{Key: key, Value: value, Err: errors.New("the schema version is managed by mk")}
+	}
+	raw, err := parseValue(f.Type, value)
+	if err != nil {
+		return &InvalidValueError{Key: key, Value: value, Err: err}
+	}
+	allowed := allowedValues(key)
+	if key == "color_scheme" {
+		allowed = m.ColorSchemeNames()
+	}
+	if allowed != nil && !slices.Contains(allowed, value) {
+		return &InvalidValueError{Key: key, Value: value, Allowed: allowed}
+	}
+	if err := checkValue(f.Type, raw); err != nil {
+		return &InvalidValueError{Key: key, Value: value, Err: err}
+	}
+	return m.update(func(disk map[string]json.RawMessage) {
+		disk[key] = raw
+	})
+}
+
+// Reset restores the default value of key in the user file, or of every
+// setting when key is empty.
+func (m *Manager) Reset(key string) error {
+	if key != "" {
+		if _, ok := field(key); !ok {
+			return unknownKey(key)
+		}
+	}
+	return m.update(func(disk map[string]json.RawMessage) {
+		if key == "" {
+			clear(disk)
+		} else {
+			delete(disk, key)
+		}
+	})
+}
+
+// Source returns the file or variable the effective value of key comes
+// from, empty for a default value.
+func (m *Manager) Source(key string) string {
+	return m.sources[key]
+}
+
+// parseValue converts the text form of a setting to JSON.
+func parseValue(t reflect.Type, value string) (json.RawMessage, error) {
+	var v any
+	switch t.Kind() {
+	case reflect.String:
+		v = value
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("expected true or false")
+		}
+		v = b
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, errors.New("expected a whole number, 0 or more")
+		}
+		v = n
+	case reflect.Uint8:
+		if len(value) != 1 {
+			return nil, errors.New("expected a single character")
+		}
+		v = value[0]
+	case reflect.Slice:
+		if strings.HasPrefix(strings.TrimSpace(value), "[") {
+			var list []string
+			if err := json.Unmarshal([]byte(value), &list); err != nil {
+				return nil, err
+			}
+			v = list
+		} else {
+			v = splitList(value)
+		}
+	case reflect.Map:
+		if strings.HasPrefix(strings.TrimSpace(value), "{") {
+			pairs := reflect.New(t)
+			if err := json.Unmarshal([]byte(value), pairs.Interface()); err != nil {
+				return nil, err
+			}
+			v = pairs.Interface()
+		} else if t.Elem().Kind() != reflect.String {
+			return nil, errors.New("expected a JSON object")
+		} else {
+			pairs := make(map[string]string)
+			for _, item := range splitList(value) {
+				name, val, ok := strings.Cut(item, "=")
+				if !ok || name == "" {
+					return nil, fmt.Errorf("expected name=value, got %q", item)
+				}
+				pairs[name] = val
+			}
+			v = pairs
+		}
+	default:
+		return nil, fmt.Errorf("unsupported setting type %s", t)
+	}
+	return json.Marshal(v)
+}
+
+// checkValue reports the errors of a setting value that its type does not
+// catch, such as invalid colors in themes.
+func checkValue(t reflect.Type, raw json.RawMessage) error {
+	if t != reflect.TypeFor[map[string]theme.Theme]() {
+		return nil
+	}
+	var themes map[string]theme.Theme
+	if err := json.Unmarshal(raw, &themes); err != nil {
+		return err
+	}
+	return errors.Join(themeProblems(themes)...)
+}
+
+// themeProblems reports the invalid colors and unknown bases of each theme.
+func themeProblems(themes map[string]theme.Theme) []error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(themes)) {
+		if err := themes[name].Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("theme %s: %w", name, err))
+		}
+	}
+	return errs
+}
+
+func splitList(value string) []string {
+	list := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func unknownKey(key string) error {
+	err := &UnknownKeyError{Key: key}
+	best := 4 // suggestions further away than this are not helpful
+	for _, k := range Keys() {
+		if d := editDistance(key, k); d < best {
+			err.Suggestion, best = k, d
+		}
+	}
+	return err
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/subut0n/mk/internal/i18n"
+)
+
+func TestSetAndGet(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	cfg, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct{ key, value, want string }{
+		{"language", "fr", "fr"},
+		{"menu_order", "frecency", "frecency"},
+		{"log_keep", "5", "5"},
+		{"notify_bell", "false", "false"},
+		{"notify_osc", "off", "off"},
+		{"custom_up_key", "z", "z"},
+		{"watch_patterns", "*.go, go.mod", `["*.go","go.mod"]`},
+		{"hidden_targets", `["help"]`, `["help"]`},
+		{"default_vars", "ENV=dev,X=1", `{"ENV":"dev","X":"1"}`},
+	}
+	for _, c := range cases {
+		if err := cfg.Set(c.key, c.value); err != nil {
+			t.Fatalf("Set(%s, %s): %v", c.key, c.value, err)
+		}
+	}
+
+	cfg2, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cases {
+		got, err := cfg2.Get(c.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("Get(%s) = %q, want %q", c.key, got, c.want)
+		}
+	}
+	if cfg2.Config.Language != i18n.LangFR || cfg2.Config.CustomUpKey != 'z' {
+		t.Errorf("unexpected config %+v", cfg2.Config)
+	}
+}
+
+func TestSetValidation(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	cfg, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var unknown *UnknownKeyError
+	if err := cfg.Set("langauge", "fr"); !errors.As(err, &unknown) || unknown.Suggestion != "language" {
+		t.Errorf("expected an unknown key error suggesting language, got %v", err)
+	}
+	if err := cfg.Set("zzzzzzzzzz", "1"); !errors.As(err, &unknown) || unknown.Suggestion != "" {
+		t.Errorf("expected no suggestion for a distant key, got %v", err)
+	}
+
+	var invalid *InvalidValueError
+	if err := cfg.Set("color_scheme", "sepia"); !errors.As(err, &invalid) || len(invalid.Allowed) != len(ColorSchemes()) {
+		t.Errorf("expected the allowed color schemes, got %v", err)
+	}
+	for key, value := range map[string]string{
+		"log_keep":      "-1",
+		"notify_bell":   "maybe",
+		"custom_up_key": "up",
+		"default_vars":  "ENV",
+	} {
+		if err := cfg.Set(key, value); !errors.As(err, &invalid) {
+			t.Errorf("Set(%s, %s): expected an invalid value error, got %v", key, value, err)
+		}
+	}
+	if cfg.Exists() {
+		t.Error("invalid settings must not be saved")
+	}
+}
+
+func TestReset(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	cfg, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range map[string]string{"language": "de", "menu_order": "recent", "log_keep": "3"} {
+		if err := cfg.Set(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := cfg.Reset("language"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Config.Language != i18n.LangEN || cfg.Config.MenuOrder != MenuOrderRecent {
+		t.Errorf("expected only language to be reset, got %+v", cfg.Config)
+	}
+
+	if err := cfg.Reset(""); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Config.MenuOrder != "" || cfg.Config.LogKeep != 0 {
+		t.Errorf("expected every setting to be reset, got %+v", cfg.Config)
+	}
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/subut0n/mk/internal/theme"
+)
+
+// ColorSchemeNames returns the accepted values of color_scheme: the
+// built-in schemes followed by the themes defined in the configuration.
+func (m *Manager) ColorSchemeNames() []string {
+	names := names(ColorSchemes())
+	for _, name := range slices.Sorted(maps.Keys(m.Config.Themes)) {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Theme returns the theme selected by color_scheme, to be resolved for the
+// background of the terminal.
+func (m *Manager) Theme() theme.Theme {
+	return ThemeOf(m.Config.ColorScheme, m.Config.Themes)
+}
+
+// ThemeOf returns the theme called scheme, among themes and the built-in
+// ones, or the rainbow theme for an unknown name. A theme of themes takes
+// precedence over a built-in one of the same name, which it adjusts.
+func ThemeOf(scheme ColorScheme, themes map[string]theme.Theme) theme.Theme {
+	name := string(scheme)
+	_, builtin := theme.Builtin(name)
+	if t, ok := themes[name]; ok {
+		if t.Base == "" && builtin {
+			t.Base = name
+		}
+		return t
+	}
+	if !builtin {
+		name = string(ColorSchemeRainbow)
+	}
+	return theme.Theme{Base: name}
+}
+
+// checkScheme falls back to the rainbow scheme when color_scheme names
+// neither a built-in scheme nor a theme of the configuration.
+func (m *Manager) checkScheme() {
+	m.schemeWarning = nil
+	scheme := string(m.Config.ColorScheme)
+	if allowed := m.ColorSchemeNames(); !slices.Contains(allowed, scheme) {
+		m.schemeWarning = fmt.Errorf("%s: %w", m.sources["color_scheme"], &InvalidValueError{Key: "color_scheme", Value: scheme, Allowed: allowed})
+		m.Config.ColorScheme = ColorSchemeRainbow
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestUserThemes(t *testing.T) {
+	writeUserConfig(t, `{"version": 1, "color_scheme": "ocean", "themes": {
+		"ocean": {"base": "deuteranopia", "title": "#0087ff", "palette": ["39", "45"]},
+		"rainbow": {"description": "245"}
+	}}`)
+
+	cfg, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Warnings()) != 0 {
+		t.Fatal(cfg.Warnings())
+	}
+	if th := cfg.Theme().Resolve(false); th.Title != "#0087ff" || len(th.Palette) != 2 || th.Cursor != "magenta" {
+		t.Errorf("unexpected theme %+v", th)
+	}
+	if th := ThemeOf(ColorSchemeRainbow, cfg.Config.Themes).Resolve(false); th.Description != "245" || len(th.Palette) != 6 {
+		t.Errorf("a theme named after a built-in one should adjust it, got %+v", th)
+	}
+	if th := ThemeOf("high-contrast", cfg.Config.Themes).Resolve(true); th.Description != "240" || th.Palette[1] != "130" {
+		t.Errorf("expected the light variant of the built-in theme, got %+v", th)
+	}
+	if got := cfg.ColorSchemeNames(); len(got) != len(ColorSchemes())+1 || got[len(got)-1] != "ocean" {
+		t.Errorf("unexpected color schemes %v", got)
+	}
+
+	if err := cfg.Set("color_scheme", "tritanopia"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("color_scheme", "ocean"); err != nil {
+		t.Fatal(err)
+	}
+	var invalid *InvalidValueError
+	if err := cfg.Set("themes", `{"dusk": {"title": "violetish"}}`); !errors.As(err, &invalid) {
+		t.Errorf("expected an invalid theme color to be refused, got %v", err)
+	}
+	if err := cfg.Set("themes", `{"dusk": {"title": "99"}}`); err != nil {
+		t.Fatal(err)
+	}
+	// ocean is gone: the scheme falls back to rainbow with a warning.
+	if cfg.Config.ColorScheme != ColorSchemeRainbow || len(cfg.Warnings()) != 1 {
+		t.Errorf("expected a fallback to rainbow, got %q and %v", cfg.Config.ColorScheme, cfg.Warnings())
+	}
+}
+
+func TestInvalidThemeColor(t *testing.T) {
+	path := writeUserConfig(t, `{"version": 1, "color_scheme": "ocean", "themes": {
+		"ocean": {"title": "#0087ff"},
+		"typo": {"title": "bleu"}
+	}}`)
+
+	cfg, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	warnings := cfg.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), "theme typo") {
+		t.Errorf("expected the invalid color to be reported, got %v", warnings)
+	}
+	if cfg.Config.ColorScheme != "ocean" || len(cfg.Config.Themes) != 2 {
+		t.Errorf("expected the themes to be kept, got %q and %v", cfg.Config.ColorScheme, cfg.Config.Themes)
+	}
+
+	// Saving another setting keeps every theme on disk.
+	if err := cfg.Set("language", "fr"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"ocean"`) || !strings.Contains(string(data), `"bleu"`) {
+		t.Errorf("expected the themes to stay in the file, got %s", data)
+	}
+	if cfg.Config.ColorScheme != "ocean" {
+		t.Errorf("expected the ocean scheme, got %q", cfg.Config.ColorScheme)
+	}
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/subut0n/mk/internal/ansi"
+	"github.com/subut0n/mk/internal/i18n"
+)
+
+// SetupStep is a question of the setup, asked with SetupOptions.Select.
+type SetupStep struct {
+	Title       string
+	Header      []string // lines shown between the title and the choices
+	Choices     []SetupChoice
+	Initial     int  // choice highlighted first
+	Step, Steps int  // position in the setup, shown when there are several steps
+	Back        bool // the user can go back to the previous step
+}
+
+// SetupChoice is an answer to a SetupStep.
+type SetupChoice struct {
+	Label, Hint string
+}
+
+var (
+	// ErrSetupBack is returned by SetupOptions.Select when the user goes back to the
+	// previous step.
+	ErrSetupBack = errors.New("back to the previous step")
+	// ErrSetupCancelled is returned when the user quits the setup.
+	ErrSetupCancelled = errors.New("setup cancelled")
+	// ErrSelectUnavailable is returned by SetupOptions.Select when the terminal cannot
+	// show menus; the setup then asks numbered questions instead.
+	ErrSelectUnavailable = errors.New("selection menus unavailable")
+)
+
+// runSteps asks the steps in order with opts.Select, going back to the
+// previous step on ErrSetupBack.
+func runSteps(opts SetupOptions, steps ...func(step SetupStep) error) error {
+	if opts.Select == nil {
+		return ErrSelectUnavailable
+	}
+	for i := 0; i < len(steps); {
+		err := steps[i](SetupStep{Step: i + 1, Steps: len(steps), Back: i > 0})
+		switch {
+		case errors.Is(err, ErrSetupBack):
+			i = max(i-1, 0)
+		case err != nil:
+			return err
+		default:
+			i++
+		}
+	}
+	return nil
+}
+
+var langChoices = []struct {
+	lang  i18n.Lang
+	label string
+}{
+	{i18n.LangEN, "English"},
+	{i18n.LangFR, "Français"},
+	{i18n.LangES, "Español"},
+	{i18n.LangDE, "Deutsch"},
+}
+
+// selectLang asks the language with opts.Select and makes it the language
+// of the next steps.
+func selectLang(lang *i18n.Lang, opts SetupOptions) func(SetupStep) error {
+	return func(step SetupStep) error {
+		step.Title = "Choose your language / Choisis ta langue:"
+		for i, c := range langChoices {
+			step.Choices = append(step.Choices, SetupChoice{Label: c.label})
+			if c.lang == i18n.Current() {
+				step.Initial = i
+			}
+		}
+		i, err := opts.Select(step)
+		if err != nil {
+			return err
+		}
+		*lang = langChoices[i].lang
+		i18n.Set(*lang)
+		return nil
+	}
+}
+
+// selectColor asks the color scheme with opts.Select, above a preview of
+// the schemes.
+func selectColor(cs *ColorScheme, opts SetupOptions) func(SetupStep) error {
+	return func(step SetupStep) error {
+		m := i18n.Get()
+		step.Title = m.ConfigColorPrompt
+		step.Header = colorPreviewLines(opts)
+		step.Choices = []SetupChoice{
+			{m.ConfigColorRainbow, m.ConfigColorRainbowHint},
+			{m.ConfigColorDeuteranopia, m.ConfigColorDeuteranopiaHint},
+			{m.ConfigColorTritanopia, m.ConfigColorTritanopiaHint},
+			{m.ConfigColorHighContrast, m.ConfigColorHighContrastHint},
+		}
+		step.Initial = max(slices.Index(ColorSchemes(), *cs), 0)
+		i, err := opts.Select(step)
+		if err != nil {
+			return err
+		}
+		*cs = ColorSchemes()[i]
+		return nil
+	}
+}
+
+// selectKeys asks the key scheme with opts.Select.
+func selectKeys(ks *KeyScheme, opts SetupOptions) func(SetupStep) error {
+	return func(step SetupStep) error {
+		m := i18n.Get()
+		step.Title = m.ConfigKeyPrompt
+		step.Choices = []SetupChoice{
+			{m.ConfigKeyArrows, m.ConfigKeyArrowsHint},
+			{m.ConfigKeyWASD, m.ConfigKeyWASDHint},
+			{m.ConfigKeyCustom, m.ConfigKeyCustomHint},
+		}
+		step.Initial = max(slices.Index(KeySchemes(), *ks), 0)
+		i, err := opts.Select(step)
+		if err != nil {
+			return err
+		}
+		*ks = KeySchemes()[i]
+		return nil
+	}
+}
+
+// confirmLang, confirmColor and confirmKeys print the answer of a step once
+// the menus are done, as the numbered questions do after each answer.
+func confirmLang() {
+	fmt.Printf("%s%s%s%s\n", ansi.Bold, ansi.Green, i18n.Get().ConfigLangConfirm, ansi.Reset)
+}
+
+func confirmColor(cs ColorScheme) {
+	m := i18n.Get()
+	labels := map[ColorScheme]string{
+		ColorSchemeRainbow:      m.ConfigColorRainbow,
+		ColorSchemeDeuteranopia: m.ConfigColorDeuteranopia,
+		ColorSchemeTritanopia:   m.ConfigColorTritanopia,
+		ColorSchemeHighContrast: m.ConfigColorHighContrast,
+	}
+	fmt.Printf("%s%s%s%s\n", ansi.Bold, ansi.Green, fmt.Sprintf(m.ConfigColorConfirm, labels[cs]), ansi.Reset)
+}
+
+func confirmKeys(ks KeyScheme) {
+	m := i18n.Get()
+	switch ks {
+	case KeySchemeArrows:
+		fmt.Printf("%s%s%s%s\n", ansi.Bold, ansi.Green, m.ConfigKeyConfirmArrows, ansi.Reset)
+	case KeySchemeWASD:
+		fmt.Printf("%s%s%s%s\n", ansi.Bold, ansi.Green, m.ConfigKeyConfirmWASD, ansi.Reset)
+	}
+}
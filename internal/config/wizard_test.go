@@ -0,0 +1,77 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/subut0n/mk/internal/i18n"
+	"github.com/subut0n/mk/internal/theme"
+)
+
+func TestRunSteps(t *testing.T) {
+	t.Cleanup(func() { i18n.Set(i18n.LangEN) })
+
+	// French first, then back from the colors to English, tritanopia and
+	// WASD.
+	answers := []struct {
+		step, choice int
+		err          error
+	}{
+		{1, 1, nil},
+		{2, 0, ErrSetupBack},
+		{1, 0, nil},
+		{2, 2, nil},
+		{3, 1, nil},
+	}
+	var asked []SetupStep
+	selectStep := func(step SetupStep) (int, error) {
+		asked = append(asked, step)
+		a := answers[len(asked)-1]
+		if step.Step != a.step {
+			t.Fatalf("question %d is step %d, want %d", len(asked), step.Step, a.step)
+		}
+		return a.choice, a.err
+	}
+
+	var (
+		lang = i18n.LangEN
+		cs   = ColorSchemeRainbow
+		ks   = KeySchemeArrows
+	)
+	opts := SetupOptions{
+		Select: selectStep,
+		Theme:  func(cs ColorScheme) theme.Theme { return ThemeOf(cs, nil).Resolve(false) },
+		Depth:  theme.Depth256,
+	}
+	if err := runSteps(opts, selectLang(&lang, opts), selectColor(&cs, opts), selectKeys(&ks, opts)); err != nil {
+		t.Fatalf("runSteps: %v", err)
+	}
+	if len(asked) != len(answers) {
+		t.Fatalf("%d questions asked, want %d", len(asked), len(answers))
+	}
+	if lang != i18n.LangEN || cs != ColorSchemeTritanopia || ks != KeySchemeWASD {
+		t.Errorf("answers = %s, %s, %s; want en, tritanopia, wasd", lang, cs, ks)
+	}
+	if asked[0].Back || !asked[1].Back {
+		t.Errorf("back allowed on steps 1 and 2 = %v, %v; want false, true", asked[0].Back, asked[1].Back)
+	}
+	if asked[2].Initial != 1 {
+		t.Errorf("language highlighted after going back = %d, want 1 (French)", asked[2].Initial)
+	}
+	if asked[1].Title == asked[3].Title {
+		t.Errorf("color step asked in %q after both French and English", asked[1].Title)
+	}
+}
+
+func TestRunStepsUnavailable(t *testing.T) {
+	lang := i18n.LangEN
+	var opts SetupOptions
+	if err := runSteps(opts, selectLang(&lang, opts)); !errors.Is(err, ErrSelectUnavailable) {
+		t.Errorf("runSteps without Select = %v, want ErrSelectUnavailable", err)
+	}
+
+	opts.Select = func(SetupStep) (int, error) { return 0, ErrSetupCancelled }
+	if err := runSteps(opts, selectLang(&lang, opts)); !errors.Is(err, ErrSetupCancelled) {
+		t.Errorf("runSteps after quitting = %v, want ErrSetupCancelled", err)
+	}
+}
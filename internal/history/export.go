@@ -0,0 +1,232 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/subut0n/mk/internal/safefile"
+)
+
+// Filter selects history entries. Zero fields match everything.
+type Filter struct {
+	Project string    // directory the run was recorded in
+	Since   time.Time // runs started at or after
+	Until   time.Time // runs started before
+	Status  Status
+}
+
+// Match reports whether e passes the filter.
+func (f Filter) Match(e Entry) bool {
+	switch {
+	case f.Project != "" && e.Directory != f.Project:
+		return false
+	case !f.Since.IsZero() && e.ExecutedAt.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !e.ExecutedAt.Before(f.Until):
+		return false
+	case f.Status != StatusUnknown && e.Status != f.Status:
+		return false
+	}
+	return true
+}
+
+// Select returns the entries matching f, most recent first.
+func (m *Manager) Select(f Filter) []Entry {
+	var entries []Entry
+	for _, e := range m.entries {
+		if f.Match(e) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// csvHeader lists the CSV columns written by WriteCSV and read by ReadEntries.
+var csvHeader = []string{"executed_at", "directory", "target", "args", "makefile", "status", "exit_code", "duration_ms", "commit", "log_path"}
+
+// WriteJSON writes entries as an indented JSON array.
+func WriteJSON(w io.Writer, entries []Entry) error {
+	if entries == nil {
+		entries = []Entry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// WriteCSV writes entries as CSV with a header row. Arguments are stored as a
+// JSON array so that values containing spaces survive a round trip.
+func WriteCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		args := ""
+		if len(e.Args) > 0 {
+			data, _ := json.Marshal(e.Args)
+			args = string(data)
+		}
+		record := []string{
+			e.ExecutedAt.Format(time.RFC3339Nano),
+			e.Directory,
+			e.Target,
+			args,
+			e.Makefile,
+			string(e.Status),
+			strconv.Itoa(e.ExitCode),
+			strconv.FormatInt(e.DurationMs, 10),
+			e.Commit,
+			e.LogPath,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadEntries parses an exported history: a JSON array (WriteJSON, or the
+// history.json of earlier versions), JSON Lines (the history file itself) or
+// CSV (WriteCSV).
+func ReadEntries(r io.Reader) ([]Entry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) == 0:
+		return nil, nil
+	case trimmed[0] == '[':
+		var entries []Entry
+		err := json.Unmarshal(trimmed, &entries)
+		return entries, err
+	case trimmed[0] == '{':
+		var entries []Entry
+		sc := bufio.NewScanner(bytes.NewReader(trimmed))
+		sc.Buffer(make([]byte, 64*1024), len(trimmed)+1)
+		for line := 1; sc.Scan(); line++ {
+			if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+				continue
+			}
+			var e Entry
+			if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			entries = append(entries, e)
+		}
+		return entries, sc.Err()
+	}
+	return readCSV(trimmed)
+}
+
+func readCSV(data []byte) ([]Entry, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	col := make(map[string]int)
+	for i, name := range records[0] {
+		col[name] = i
+	}
+	for _, required := range []string{"executed_at", "directory", "target"} {
+		if _, ok := col[required]; !ok {
+			return nil, fmt.Errorf("missing CSV column %q", required)
+		}
+	}
+
+	var entries []Entry
+	for n, rec := range records[1:] {
+		get := func(name string) string {
+			if i, ok := col[name]; ok && i < len(rec) {
+				return rec[i]
+			}
+			return ""
+		}
+		at, err := time.Parse(time.RFC3339Nano, get("executed_at"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", n+2, err)
+		}
+		e := Entry{
+			Target:     get("target"),
+			Directory:  get("directory"),
+			ExecutedAt: at,
+			Makefile:   get("makefile"),
+			Status:     Status(get("status")),
+			Commit:     get("commit"),
+			LogPath:    get("log_path"),
+		}
+		if args := get("args"); args != "" {
+			if err := json.Unmarshal([]byte(args), &e.Args); err != nil {
+				return nil, fmt.Errorf("row %d: args: %w", n+2, err)
+			}
+		}
+		e.ExitCode, _ = strconv.Atoi(get("exit_code"))
+		e.DurationMs, _ = strconv.ParseInt(get("duration_ms"), 10, 64)
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Import merges entries from another history into this one. Entries already
+// present, identified by their start time and target, are skipped, and the
+// Privacy settings apply as they do to Record. It returns the number of
+// entries added.
+func (m *Manager) Import(entries []Entry) (int, error) {
+	unlock, err := safefile.Lock(m.filePath)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	if err := m.reload(); err != nil {
+		return 0, err
+	}
+
+	type key struct {
+		at     int64
+		target string
+	}
+	seen := make(map[key]bool, len(m.entries))
+	for _, e := range m.entries {
+		seen[key{e.ExecutedAt.UnixNano(), e.Target}] = true
+	}
+	added := 0
+	for _, e := range entries {
+		k := key{e.ExecutedAt.UnixNano(), e.Target}
+		if e.Target == "" || e.ExecutedAt.IsZero() || seen[k] || m.Privacy.Excluded(e) {
+			continue
+		}
+		seen[k] = true
+		e.Args = m.Privacy.Redact(e.Args)
+		m.entries = append(m.entries, e)
+		added++
+	}
+	if added == 0 {
+		return 0, nil
+	}
+
+	sort.SliceStable(m.entries, func(i, j int) bool {
+		return m.entries[i].ExecutedAt.After(m.entries[j].ExecutedAt)
+	})
+	if err := m.rewrite(); err != nil {
+		return 0, err
+	}
+	if m.needsCompaction() {
+		return added, m.compact()
+	}
+	return added, nil
+}
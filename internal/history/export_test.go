@@ -0,0 +1,138 @@
+package history
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func sampleEntries() []Entry {
+	at := time.Date(2026, 5, 10, 9, 30, 0, 123456789, time.UTC)
+	return []Entry{
+		{Target: "deploy", Directory: "/srv/app", ExecutedAt: at, Makefile: "/srv/app/Makefile", Args: []string{"ENV=prod", "MSG=a b"}, Status: StatusFailed, ExitCode: 2, DurationMs: 1500, Commit: "abc123", LogPath: "/logs/deploy.log"},
+		{Target: "build", Directory: "/srv/app", ExecutedAt: at.Add(-time.Hour), Status: StatusSuccess, DurationMs: 20},
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	for name, write := range map[string]func(*bytes.Buffer, []Entry) error{
+		"json": func(b *bytes.Buffer, e []Entry) error { return WriteJSON(b, e) },
+		"csv":  func(b *bytes.Buffer, e []Entry) error { return WriteCSV(b, e) },
+	} {
+		var buf bytes.Buffer
+		if err := write(&buf, sampleEntries()); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		got, err := ReadEntries(&buf)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		want := sampleEntries()
+		if len(got) != len(want) {
+			t.Fatalf("%s: expected %d entries, got %d", name, len(want), len(got))
+		}
+		for i := range want {
+			if !got[i].ExecutedAt.Equal(want[i].ExecutedAt) {
+				t.Errorf("%s: entry %d: time %v, want %v", name, i, got[i].ExecutedAt, want[i].ExecutedAt)
+			}
+			got[i].ExecutedAt, want[i].ExecutedAt = time.Time{}, time.Time{}
+			if !reflect.DeepEqual(got[i], want[i]) {
+				t.Errorf("%s: entry %d: got %+v, want %+v", name, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestReadEntriesJSONLines(t *testing.T) {
+	input := `{"target":"build","directory":"/p","executed_at":"2026-05-10T09:30:00Z"}
+{"target":"test","directory":"/p","executed_at":"2026-05-10T09:31:00Z"}
+`
+	got, err := ReadEntries(bytes.NewBufferString(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[1].Target != "test" {
+		t.Errorf("unexpected entries %+v", got)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	entries := sampleEntries()
+	day := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		f    Filter
+		want int
+	}{
+		{Filter{}, 2},
+		{Filter{Project: "/srv/app"}, 2},
+		{Filter{Project: "/other"}, 0},
+		{Filter{Status: StatusFailed}, 1},
+		{Filter{Since: day.Add(9 * time.Hour)}, 1},
+		{Filter{Until: day.Add(9 * time.Hour)}, 1},
+	}
+	for _, c := range cases {
+		n := 0
+		for _, e := range entries {
+			if c.f.Match(e) {
+				n++
+			}
+		}
+		if n != c.want {
+			t.Errorf("%+v: expected %d matches, got %d", c.f, c.want, n)
+		}
+	}
+}
+
+func TestImportDeduplicates(t *testing.T) {
+	m := setupTestHistory(t)
+	existing := sampleEntries()[1]
+	if err := m.Record(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	added, err := m.Import(sampleEntries())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 1 {
+		t.Errorf("expected 1 new entry, got %d", added)
+	}
+	if added, _ := m.Import(sampleEntries()); added != 0 {
+		t.Errorf("importing twice must not duplicate entries, added %d", added)
+	}
+
+	m2 := &Manager{filePath: m.filePath}
+	if err := m2.reload(); err != nil {
+		t.Fatal(err)
+	}
+	entries := m2.All()
+	if len(entries) != 2 || entries[0].Target != "deploy" || entries[1].Target != "build" {
+		t.Errorf("expected [deploy build] by time, got %+v", entries)
+	}
+}
+
+func TestImportPrivacy(t *testing.T) {
+	m := setupTestHistory(t)
+	m.Privacy = Privacy{ExcludeTargets: []string{"build"}, RedactArgs: []string{"env"}}
+
+	added, err := m.Import(sampleEntries())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 1 {
+		t.Errorf("expected 1 new entry, got %d", added)
+	}
+	entries := m.All()
+	if len(entries) != 1 || entries[0].Target != "deploy" {
+		t.Fatalf("excluded target imported: %+v", entries)
+	}
+	if want := []string{"ENV=" + RedactedValue, "MSG=a b"}; !reflect.DeepEqual(entries[0].Args, want) {
+		t.Errorf("imported args = %q, want %q", entries[0].Args, want)
+	}
+
+	m.Privacy = Privacy{ExcludeDirs: []string{"/srv"}}
+	if added, _ := m.Import(sampleEntries()); added != 0 {
+		t.Errorf("entries of an excluded directory imported: %d", added)
+	}
+}
@@ -1,62 +1,154 @@
 package history
 
 import (
-	"encoding/json"
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/subut0n/mk/internal/paths"
+	"github.com/subut0n/mk/internal/safefile"
+)
+
+// Status describes how a recorded run ended.
+type Status string
+
+const (
+	StatusUnknown     Status = "" // entries recorded before outcomes were tracked
+	StatusSuccess     Status = "success"
+	StatusFailed      Status = "failed"
+	StatusInterrupted Status = "interrupted"
 )
 
 // Entry represents a single command history record.
 type Entry struct {
-	Target    string    `json:"target"`
-	Directory string    `json:"directory"`
-	ExecutedAt time.Time `json:"executed_at"`
+	Target     string    `json:"target"`
+	Directory  string    `json:"directory"`
+	ExecutedAt time.Time `json:"executed_at"` // start of the run
+	Makefile   string    `json:"makefile,omitempty"`
+	Args       []string  `json:"args,omitempty"` // extra make arguments (e.g. VAR=value)
+	Status     Status    `json:"status,omitempty"`
+	ExitCode   int       `json:"exit_code,omitempty"`
+	DurationMs int64     `json:"duration_ms,omitempty"`
+	Commit     string    `json:"commit,omitempty"` // git commit of Directory at run time
+	LogPath    string    `json:"log_path,omitempty"`
+}
+
+// Duration returns the wall-clock duration of the run.
+func (e Entry) Duration() time.Duration {
+	return time.Duration(e.DurationMs) * time.Millisecond
+}
+
+// Retention bounds the size of the history. Zero fields use the defaults.
+type Retention struct {
+	MaxEntries int           // most recent runs kept
+	MaxAge     time.Duration // older runs are dropped
+	MaxBytes   int64         // size of the history file
 }
 
-// Manager handles persistent command history.
+// DefaultRetention keeps a year of history, up to 10,000 runs or 10 MiB.
+var DefaultRetention = Retention{
+	MaxEntries: 10000,
+	MaxAge:     365 * 24 * time.Hour,
+	MaxBytes:   10 << 20,
+}
+
+// Manager handles persistent command history. Runs are appended to a JSON
+// Lines file, one entry per line, under the state directory; the file is
+// compacted when it outgrows its Retention. Several mk processes may share
+// the file: every change is made under a file lock.
 type Manager struct {
-	filePath string
-	entries  []Entry
+	filePath  string
+	entries   []Entry // most recent first
+	recovered *safefile.CorruptError
+
+	Retention Retention
+	Privacy   Privacy
 }
 
-// New creates a new history Manager.
+// New creates a new history Manager, migrating the history.json file of
+// earlier versions from the configuration directory if needed. Entries of
+// deleted directories are kept until Prune is called: the directory of an
+// imported entry may only exist on another machine.
 func New() (*Manager, error) {
-	dir, err := os.UserConfigDir()
+	state, err := paths.StateDir()
 	if err != nil {
-		dir = os.TempDir()
+		return nil, err
 	}
 
-	configDir := filepath.Join(dir, "mk")
-	if err := os.MkdirAll(configDir, 0755); err != nil {
+	m := &Manager{
+		filePath:  filepath.Join(state, "history.jsonl"),
+		Retention: DefaultRetention,
+	}
+
+	unlock, err := safefile.Lock(m.filePath)
+	if err != nil {
 		return nil, err
 	}
+	defer unlock()
 
-	m := &Manager{
-		filePath: filepath.Join(configDir, "history.json"),
+	if dir, err := os.UserConfigDir(); err == nil {
+		_ = m.migrate(filepath.Join(dir, "mk", "history.json"))
 	}
 
 	// Load existing history
-	_ = m.load()
+	_ = m.reload()
 
 	return m, nil
 }
 
-// Add records a target execution in the history.
+// Recovered reports the corrupted history file that was repaired or moved
+// aside while loading, if any, so that the user can be warned about it.
+func (m *Manager) Recovered() *safefile.CorruptError {
+	return m.recovered
+}
+
+// Add records a target execution in the current directory.
 func (m *Manager) Add(target string) error {
-	dir, _ := os.Getwd()
-	m.entries = append([]Entry{{
-		Target:     target,
-		Directory:  dir,
-		ExecutedAt: time.Now(),
-	}}, m.entries...)
+	return m.Record(Entry{Target: target})
+}
 
-	// Keep only the 50 most recent entries
-	if len(m.entries) > 50 {
-		m.entries = m.entries[:50]
+// Record appends a completed run to the history. Directory defaults to the
+// current working directory and ExecutedAt to the current time. Runs
+// excluded by the Privacy settings are not recorded, and matching argument
+// values are redacted.
+func (m *Manager) Record(e Entry) error {
+	if e.Directory == "" {
+		e.Directory, _ = os.Getwd()
 	}
+	if e.ExecutedAt.IsZero() {
+		e.ExecutedAt = time.Now()
+	}
+	if m.Privacy.Excluded(e) {
+		return nil
+	}
+	e.Args = m.Privacy.Redact(e.Args)
 
-	return m.save()
+	unlock, err := safefile.Lock(m.filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := m.append(e); err != nil {
+		return err
+	}
+	m.entries = append([]Entry{e}, m.entries...)
+
+	if m.needsCompaction() {
+		return m.compact()
+	}
+	return nil
+}
+
+// Compact rewrites the history file, dropping the runs that fall outside the
+// retention limits.
+func (m *Manager) Compact() error {
+	unlock, err := safefile.Lock(m.filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return m.compact()
 }
 
 // Recent returns the n most recent history entries.
@@ -67,18 +159,107 @@ func (m *Manager) Recent(n int) []Entry {
 	return m.entries[:n]
 }
 
-func (m *Manager) load() error {
-	data, err := os.ReadFile(m.filePath)
+// InDirectory returns the entries recorded in dir, most recent first.
+func (m *Manager) InDirectory(dir string) []Entry {
+	var entries []Entry
+	for _, e := range m.entries {
+		if e.Directory == dir {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Frecency scores the targets run in dir by how often and how recently they
+// ran: every run adds a weight that decreases with its age, so that a target
+// used daily outranks one run many times long ago.
+func (m *Manager) Frecency(dir string, now time.Time) map[string]float64 {
+	scores := make(map[string]float64)
+	for _, e := range m.InDirectory(dir) {
+		scores[e.Target] += recencyWeight(now.Sub(e.ExecutedAt))
+	}
+	return scores
+}
+
+// recencyWeight buckets a run's age, in the spirit of Firefox's frecency.
+func recencyWeight(age time.Duration) float64 {
+	const day = 24 * time.Hour
+	switch {
+	case age < 4*day:
+		return 100
+	case age < 14*day:
+		return 70
+	case age < 31*day:
+		return 50
+	case age < 90*day:
+		return 30
+	default:
+		return 10
+	}
+}
+
+// All returns every entry, most recent first.
+func (m *Manager) All() []Entry {
+	return m.entries
+}
+
+// Delete removes the entry recorded for the same run as e (same target,
+// directory and start time) and rewrites the history.
+func (m *Manager) Delete(e Entry) error {
+	unlock, err := safefile.Lock(m.filePath)
 	if err != nil {
 		return err
 	}
-	return json.Unmarshal(data, &m.entries)
+	defer unlock()
+
+	if err := m.reload(); err != nil {
+		return err
+	}
+	for i, x := range m.entries {
+		if x.Target == e.Target && x.Directory == e.Directory && x.ExecutedAt.Equal(e.ExecutedAt) {
+			m.entries = append(m.entries[:i:i], m.entries[i+1:]...)
+			return m.rewrite()
+		}
+	}
+	return nil
 }
 
-func (m *Manager) save() error {
-	data, err := json.MarshalIndent(m.entries, "", "  ")
+// Clear removes the entries matching f and returns how many were removed.
+func (m *Manager) Clear(f Filter) (int, error) {
+	unlock, err := safefile.Lock(m.filePath)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	defer unlock()
+
+	if err := m.reload(); err != nil {
+		return 0, err
+	}
+	kept := m.entries[:0]
+	for _, e := range m.entries {
+		if !f.Match(e) {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(m.entries) - len(kept)
+	m.entries = kept
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, m.rewrite()
+}
+
+// Prune removes the entries recorded in directories that have since been
+// deleted and returns how many were removed.
+func (m *Manager) Prune() (int, error) {
+	unlock, err := safefile.Lock(m.filePath)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	if err := m.reload(); err != nil {
+		return 0, err
 	}
-	return os.WriteFile(m.filePath, data, 0600)
+	return m.prune()
 }
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func setupTestHistory(t *testing.T) *Manager {
@@ -15,7 +17,7 @@ func setupTestHistory(t *testing.T) *Manager {
 		t.Fatal(err)
 	}
 	return &Manager{
-		filePath: filepath.Join(configDir, "history.json"),
+		filePath: filepath.Join(configDir, "history.jsonl"),
 	}
 }
 
@@ -42,10 +44,12 @@ func TestAddAndRecent(t *testing.T) {
 	}
 }
 
-func TestCap50(t *testing.T) {
+func TestRetentionMaxEntries(t *testing.T) {
 	m := setupTestHistory(t)
+	m.Retention = Retention{MaxEntries: 50}
 
-	for i := 0; i < 60; i++ {
+	// Compaction runs once the limit is exceeded by 10%, i.e. on the 56th run.
+	for i := 0; i < 56; i++ {
 		if err := m.Add("target"); err != nil {
 			t.Fatal(err)
 		}
@@ -53,8 +57,55 @@ func TestCap50(t *testing.T) {
 
 	entries := m.Recent(100)
 	if len(entries) != 50 {
-		t.Fatalf("expected cap of 50 entries, got %d", len(entries))
+		t.Fatalf("expected 50 entries after compaction, got %d", len(entries))
 	}
+	if lines := countLines(t, m.filePath); lines != 50 {
+		t.Errorf("expected 50 lines on disk, got %d", lines)
+	}
+}
+
+func TestRetentionMaxAge(t *testing.T) {
+	m := setupTestHistory(t)
+	m.Retention = Retention{MaxAge: 30 * 24 * time.Hour}
+
+	m.Record(Entry{Target: "old", Directory: "/p", ExecutedAt: time.Now().AddDate(0, -2, 0)})
+	m.Record(Entry{Target: "new", Directory: "/p"})
+
+	entries := m.Recent(10)
+	if len(entries) != 1 || entries[0].Target != "new" {
+		t.Fatalf("expected only the recent run to be kept, got %+v", entries)
+	}
+}
+
+func TestRetentionMaxBytes(t *testing.T) {
+	m := setupTestHistory(t)
+	m.Retention = Retention{MaxBytes: 2000}
+
+	for i := 0; i < 40; i++ {
+		if err := m.Record(Entry{Target: "target", Directory: "/some/project/directory"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	info, err := os.Stat(m.filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() > 2000 {
+		t.Errorf("history file exceeds its size limit: %d bytes", info.Size())
+	}
+	if len(m.Recent(100)) == 0 {
+		t.Error("compaction should keep the most recent runs")
+	}
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Count(string(data), "\n")
 }
 
 func TestRecentOrder(t *testing.T) {
@@ -115,21 +166,258 @@ func TestPersistence(t *testing.T) {
 	if err := m.Add("build"); err != nil {
 		t.Fatal(err)
 	}
+	if err := m.Add("test"); err != nil {
+		t.Fatal(err)
+	}
 
-	// Read back from disk
+	// Read back from disk: one JSON object per line, oldest first
 	data, err := os.ReadFile(m.filePath)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	var entries []Entry
-	if err := json.Unmarshal(data, &entries); err != nil {
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 persisted lines, got %d", len(lines))
+	}
+	var e Entry
+	if err := json.Unmarshal([]byte(lines[0]), &e); err != nil {
 		t.Fatal(err)
 	}
-	if len(entries) != 1 {
-		t.Fatalf("expected 1 persisted entry, got %d", len(entries))
+	if e.Target != "build" {
+		t.Errorf("expected 'build', got %q", e.Target)
+	}
+}
+
+func TestMigrateLegacyHistory(t *testing.T) {
+	m := setupTestHistory(t)
+	legacy := filepath.Join(t.TempDir(), "history.json")
+	old := []Entry{
+		{Target: "test", Directory: "/p", ExecutedAt: time.Now()},
+		{Target: "build", Directory: "/p", ExecutedAt: time.Now().Add(-time.Hour)},
+	}
+	data, _ := json.Marshal(old)
+	if err := os.WriteFile(legacy, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.migrate(legacy); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(legacy + ".migrated"); err != nil {
+		t.Errorf("legacy file should be kept as .migrated: %v", err)
+	}
+
+	m2 := &Manager{filePath: m.filePath}
+	if err := m2.reload(); err != nil {
+		t.Fatal(err)
+	}
+	entries := m2.Recent(10)
+	if len(entries) != 2 || entries[0].Target != "test" || entries[1].Target != "build" {
+		t.Fatalf("expected [test build], got %+v", entries)
+	}
+
+	// An existing history is never overwritten by a later migration.
+	if err := os.WriteFile(legacy, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	m2.Add("lint")
+	m2.migrate(legacy)
+	if m2.reload(); len(m2.Recent(10)) != 3 {
+		t.Errorf("migration must not replace an existing history")
+	}
+}
+
+func TestTruncatedLineDropped(t *testing.T) {
+	m := setupTestHistory(t)
+	m.Add("build")
+	f, err := os.OpenFile(m.filePath, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"target":"te`) // a write cut short by a crash
+	f.Close()
+
+	m2 := &Manager{filePath: m.filePath}
+	if err := m2.reload(); err != nil {
+		t.Fatal(err)
+	}
+	if entries := m2.Recent(10); len(entries) != 1 || entries[0].Target != "build" {
+		t.Fatalf("expected the readable entry to be kept, got %+v", entries)
+	}
+	if rec := m2.Recovered(); rec == nil || rec.Dropped != 1 {
+		t.Fatalf("expected one dropped line to be reported, got %+v", rec)
+	}
+	if lines := countLines(t, m.filePath); lines != 1 {
+		t.Errorf("expected the file to be repaired, got %d lines", lines)
+	}
+}
+
+func TestRecordCompletedRun(t *testing.T) {
+	m := setupTestHistory(t)
+
+	start := time.Now().Add(-2 * time.Second)
+	err := m.Record(Entry{
+		Target:     "test",
+		Directory:  "/src/project",
+		ExecutedAt: start,
+		Makefile:   "/src/project/Makefile",
+		Args:       []string{"VERBOSE=1"},
+		Status:     StatusFailed,
+		ExitCode:   2,
+		DurationMs: 1500,
+		Commit:     "abc1234",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := m.Recent(1)[0]
+	if e.Status != StatusFailed || e.ExitCode != 2 {
+		t.Errorf("expected failed run with exit code 2, got %q/%d", e.Status, e.ExitCode)
+	}
+	if e.Duration() != 1500*time.Millisecond {
+		t.Errorf("expected 1.5s duration, got %s", e.Duration())
+	}
+	if !e.ExecutedAt.Equal(start) {
+		t.Errorf("expected ExecutedAt to be kept, got %s", e.ExecutedAt)
+	}
+	if len(e.Args) != 1 || e.Args[0] != "VERBOSE=1" {
+		t.Errorf("expected args to be kept, got %v", e.Args)
+	}
+}
+
+func TestRecordDefaults(t *testing.T) {
+	m := setupTestHistory(t)
+
+	if err := m.Record(Entry{Target: "build"}); err != nil {
+		t.Fatal(err)
+	}
+	e := m.Recent(1)[0]
+	wd, _ := os.Getwd()
+	if e.Directory != wd {
+		t.Errorf("expected directory %q, got %q", wd, e.Directory)
+	}
+	if e.ExecutedAt.IsZero() {
+		t.Error("expected ExecutedAt to default to now")
+	}
+}
+
+func TestInDirectory(t *testing.T) {
+	m := setupTestHistory(t)
+
+	m.Record(Entry{Target: "build", Directory: "/a"})
+	m.Record(Entry{Target: "lint", Directory: "/b"})
+	m.Record(Entry{Target: "test", Directory: "/a"})
+
+	entries := m.InDirectory("/a")
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Target != "test" || entries[1].Target != "build" {
+		t.Errorf("expected [test build], got [%s %s]", entries[0].Target, entries[1].Target)
+	}
+	if got := m.InDirectory("/c"); len(got) != 0 {
+		t.Errorf("expected no entries, got %d", len(got))
+	}
+}
+
+func TestDelete(t *testing.T) {
+	m := setupTestHistory(t)
+
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	m.Record(Entry{Target: "build", Directory: "/a", ExecutedAt: start})
+	m.Record(Entry{Target: "build", Directory: "/a", ExecutedAt: start.Add(time.Minute)})
+	m.Record(Entry{Target: "test", Directory: "/a", ExecutedAt: start})
+
+	if err := m.Delete(Entry{Target: "build", Directory: "/a", ExecutedAt: start}); err != nil {
+		t.Fatal(err)
+	}
+
+	m2 := &Manager{filePath: m.filePath}
+	if err := m2.load(); err != nil {
+		t.Fatal(err)
+	}
+	entries := m2.All()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries after delete, got %d", len(entries))
+	}
+	if entries[0].Target != "test" || !entries[1].ExecutedAt.Equal(start.Add(time.Minute)) {
+		t.Errorf("wrong entry deleted: %+v", entries)
+	}
+}
+
+func TestFrecency(t *testing.T) {
+	m := setupTestHistory(t)
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// "lint" ran three times two months ago, "test" twice this week.
+	for i := 0; i < 3; i++ {
+		m.Record(Entry{Target: "lint", Directory: "/p", ExecutedAt: now.AddDate(0, -2, 0)})
+	}
+	m.Record(Entry{Target: "test", Directory: "/p", ExecutedAt: now.Add(-time.Hour)})
+	m.Record(Entry{Target: "test", Directory: "/p", ExecutedAt: now.Add(-48 * time.Hour)})
+	m.Record(Entry{Target: "deploy", Directory: "/other", ExecutedAt: now})
+
+	scores := m.Frecency("/p", now)
+	if scores["test"] <= scores["lint"] {
+		t.Errorf("recent runs should outrank old ones: test=%v lint=%v", scores["test"], scores["lint"])
+	}
+	if scores["lint"] == 0 {
+		t.Error("old runs still count")
+	}
+	if _, ok := scores["deploy"]; ok {
+		t.Error("runs from other directories must be ignored")
+	}
+}
+
+func TestRecordMergesConcurrentWriters(t *testing.T) {
+	m1 := setupTestHistory(t)
+	m2 := &Manager{filePath: m1.filePath}
+
+	// Both processes loaded the (empty) history before either finished.
+	if err := m1.Add("build"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m2.Add("test"); err != nil {
+		t.Fatal(err)
+	}
+
+	m3 := &Manager{filePath: m1.filePath}
+	if err := m3.reload(); err != nil {
+		t.Fatal(err)
+	}
+	entries := m3.Recent(10)
+	if len(entries) != 2 || entries[0].Target != "test" || entries[1].Target != "build" {
+		t.Fatalf("expected [test build], got %+v", entries)
+	}
+}
+
+func TestCorruptedFileMovedAside(t *testing.T) {
+	m := setupTestHistory(t)
+	if err := os.WriteFile(m.filePath, []byte(`[{"target": "build"`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.reload(); err != nil {
+		t.Fatal(err)
+	}
+	rec := m.Recovered()
+	if rec == nil {
+		t.Fatal("expected the corrupted file to be reported")
+	}
+	data, err := os.ReadFile(rec.Backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `[{"target": "build"` {
+		t.Errorf("backup content lost: %q", data)
+	}
+
+	if err := m.Add("test"); err != nil {
+		t.Fatal(err)
 	}
-	if entries[0].Target != "build" {
-		t.Errorf("expected 'build', got %q", entries[0].Target)
+	if entries := m.Recent(10); len(entries) != 1 {
+		t.Errorf("expected a fresh history, got %d entries", len(entries))
 	}
 }
@@ -0,0 +1,98 @@
+package history
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// RedactedValue replaces the value of arguments matched by Privacy.RedactArgs.
+const RedactedValue = "[redacted]"
+
+// Privacy keeps sensitive runs out of the history. Patterns use the syntax of
+// path.Match.
+type Privacy struct {
+	ExcludeTargets []string // targets never recorded
+	ExcludeDirs    []string // directories, and their subdirectories, never recorded
+	RedactArgs     []string // variable names (VAR=value) whose value is redacted, case-insensitive
+}
+
+// Excluded reports whether a run of e must not be recorded.
+func (p Privacy) Excluded(e Entry) bool {
+	for _, pattern := range p.ExcludeTargets {
+		if ok, _ := path.Match(pattern, e.Target); ok {
+			return true
+		}
+	}
+	for _, pattern := range p.ExcludeDirs {
+		pattern = expandHome(pattern)
+		for dir := e.Directory; ; dir = filepath.Dir(dir) {
+			if ok, _ := filepath.Match(pattern, dir); ok {
+				return true
+			}
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+	}
+	return false
+}
+
+// Redact returns args with the values of matching variables replaced by
+// RedactedValue. args itself is left untouched.
+func (p Privacy) Redact(args []string) []string {
+	if len(p.RedactArgs) == 0 {
+		return args
+	}
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		redacted[i] = arg
+		name, _, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			continue
+		}
+		for _, pattern := range p.RedactArgs {
+			if ok, _ := path.Match(strings.ToUpper(pattern), strings.ToUpper(name)); ok {
+				redacted[i] = name + "=" + RedactedValue
+				break
+			}
+		}
+	}
+	return redacted
+}
+
+// HasRedactedArgs reports whether some arguments of e were redacted, in which
+// case the run cannot be repeated from the history.
+func (e Entry) HasRedactedArgs() bool {
+	for _, arg := range e.Args {
+		if _, value, ok := strings.Cut(arg, "="); ok && value == RedactedValue {
+			return true
+		}
+	}
+	return false
+}
+
+// expandHome replaces a leading "~/" with the user's home directory.
+func expandHome(pattern string) string {
+	rest, ok := strings.CutPrefix(pattern, "~/")
+	if !ok {
+		return pattern
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return pattern
+	}
+	return filepath.Join(home, rest)
+}
+
+// gone reports whether dir was deleted: it no longer exists but its parent
+// does. Directories whose parent is missing too, such as those of an
+// unmounted drive or of history imported from another machine, are kept.
+func gone(dir string) bool {
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		return false
+	}
+	_, err := os.Stat(filepath.Dir(dir))
+	return err == nil
+}
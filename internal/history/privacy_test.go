@@ -0,0 +1,138 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPrivacyExcluded(t *testing.T) {
+	p := Privacy{
+		ExcludeTargets: []string{"deploy-*"},
+		ExcludeDirs:    []string{"/srv/secret", "/home/*/private"},
+	}
+	cases := []struct {
+		e    Entry
+		want bool
+	}{
+		{Entry{Target: "deploy-secrets", Directory: "/srv/app"}, true},
+		{Entry{Target: "deploy", Directory: "/srv/app"}, false},
+		{Entry{Target: "build", Directory: "/srv/secret"}, true},
+		{Entry{Target: "build", Directory: "/srv/secret/sub"}, true},
+		{Entry{Target: "build", Directory: "/srv/secrets"}, false},
+		{Entry{Target: "build", Directory: "/home/me/private/repo"}, true},
+	}
+	for _, c := range cases {
+		if got := p.Excluded(c.e); got != c.want {
+			t.Errorf("Excluded(%s in %s) = %v, want %v", c.e.Target, c.e.Directory, got, c.want)
+		}
+	}
+}
+
+func TestPrivacyRedact(t *testing.T) {
+	p := Privacy{RedactArgs: []string{"*token*", "PASSWORD"}}
+	args := []string{"API_TOKEN=abc", "password=hunter2", "ENV=prod", "-j4"}
+	got := p.Redact(args)
+	want := []string{"API_TOKEN=" + RedactedValue, "password=" + RedactedValue, "ENV=prod", "-j4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if args[0] != "API_TOKEN=abc" {
+		t.Error("Redact must not modify its argument")
+	}
+	if !(Entry{Args: got}).HasRedactedArgs() || (Entry{Args: args}).HasRedactedArgs() {
+		t.Error("HasRedactedArgs does not detect redacted values")
+	}
+}
+
+func TestRecordAppliesPrivacy(t *testing.T) {
+	m := setupTestHistory(t)
+	m.Privacy = Privacy{ExcludeTargets: []string{"secret"}, RedactArgs: []string{"KEY"}}
+
+	if err := m.Record(Entry{Target: "secret", Directory: "/p"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Record(Entry{Target: "build", Directory: "/p", Args: []string{"KEY=s3cr3t"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	m2 := &Manager{filePath: m.filePath}
+	if err := m2.reload(); err != nil {
+		t.Fatal(err)
+	}
+	entries := m2.All()
+	if len(entries) != 1 || entries[0].Target != "build" {
+		t.Fatalf("expected only the build run, got %+v", entries)
+	}
+	if entries[0].Args[0] != "KEY="+RedactedValue {
+		t.Errorf("expected redacted argument, got %q", entries[0].Args[0])
+	}
+}
+
+func TestClear(t *testing.T) {
+	m := setupTestHistory(t)
+	for _, e := range []Entry{
+		{Target: "a", Directory: "/p1"},
+		{Target: "b", Directory: "/p2"},
+		{Target: "c", Directory: "/p1"},
+	} {
+		if err := m.Record(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := m.Clear(Filter{Project: "/p1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || len(m.All()) != 1 || m.All()[0].Target != "b" {
+		t.Errorf("expected 2 removed and [b] left, got %d and %+v", n, m.All())
+	}
+
+	if n, _ := m.Clear(Filter{}); n != 1 || len(m.All()) != 0 {
+		t.Errorf("expected the history to be empty, removed %d", n)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	m := setupTestHistory(t)
+	root := t.TempDir()
+	kept, deleted := filepath.Join(root, "kept"), filepath.Join(root, "deleted")
+	for _, dir := range []string{kept, deleted} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	for i, dir := range []string{kept, deleted, "/nonexistent-mount/project"} {
+		if err := m.Record(Entry{Target: "build", Directory: dir, ExecutedAt: now.Add(time.Duration(i) * time.Second)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Remove(deleted); err != nil {
+		t.Fatal(err)
+	}
+
+	// Loading the history does not prune it.
+	if err := m.reload(); err != nil || len(m.All()) != 3 {
+		t.Fatalf("reload = %v with %d entries, want 3", err, len(m.All()))
+	}
+
+	n, err := m.Prune()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 pruned entry, got %d", n)
+	}
+	for _, e := range m.All() {
+		if e.Directory == deleted {
+			t.Error("entry of the deleted directory was kept")
+		}
+	}
+	if len(m.All()) != 2 {
+		t.Errorf("expected 2 entries left, got %d", len(m.All()))
+	}
+}
@@ -0,0 +1,233 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/subut0n/mk/internal/safefile"
+)
+
+// The functions below access the history file and must be called with its
+// lock held.
+
+// effective returns the retention limits with defaults for unset fields.
+func (r Retention) effective() Retention {
+	if r.MaxEntries <= 0 {
+		r.MaxEntries = DefaultRetention.MaxEntries
+	}
+	if r.MaxAge <= 0 {
+		r.MaxAge = DefaultRetention.MaxAge
+	}
+	if r.MaxBytes <= 0 {
+		r.MaxBytes = DefaultRetention.MaxBytes
+	}
+	return r
+}
+
+// append writes e at the end of the history file.
+func (m *Manager) append(e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(m.filePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// needsCompaction reports whether the history has outgrown its retention
+// limits. Some slack is allowed so that the file is not rewritten on every
+// run once a limit is reached.
+func (m *Manager) needsCompaction() bool {
+	r := m.Retention.effective()
+	if len(m.entries) > r.MaxEntries+r.MaxEntries/10 {
+		return true
+	}
+	if n := len(m.entries); n > 0 && time.Since(m.entries[n-1].ExecutedAt) > r.MaxAge+24*time.Hour {
+		return true
+	}
+	info, err := os.Stat(m.filePath)
+	return err == nil && info.Size() > r.MaxBytes
+}
+
+// compact reloads the history and rewrites it within the retention limits.
+func (m *Manager) compact() error {
+	if err := m.reload(); err != nil {
+		return err
+	}
+	r := m.Retention.effective()
+
+	cutoff := time.Now().Add(-r.MaxAge)
+	kept := m.entries[:0]
+	for _, e := range m.entries {
+		if e.ExecutedAt.After(cutoff) && len(kept) < r.MaxEntries {
+			kept = append(kept, e)
+		}
+	}
+	m.entries = kept
+
+	// Leave room below the size limit so that appends do not immediately
+	// trigger another compaction.
+	for size := encodedSize(m.entries); size > r.MaxBytes*9/10 && len(m.entries) > 0; {
+		last := m.entries[len(m.entries)-1]
+		size -= encodedSize([]Entry{last})
+		m.entries = m.entries[:len(m.entries)-1]
+	}
+	return m.rewrite()
+}
+
+// prune drops the entries of deleted directories and rewrites the file if
+// any were dropped.
+func (m *Manager) prune() (int, error) {
+	deleted := make(map[string]bool)
+	kept := m.entries[:0]
+	for _, e := range m.entries {
+		d, seen := deleted[e.Directory]
+		if !seen {
+			d = gone(e.Directory)
+			deleted[e.Directory] = d
+		}
+		if !d {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(m.entries) - len(kept)
+	m.entries = kept
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, m.rewrite()
+}
+
+func encodedSize(entries []Entry) int64 {
+	var n int64
+	for _, e := range entries {
+		line, _ := json.Marshal(e)
+		n += int64(len(line)) + 1
+	}
+	return n
+}
+
+// rewrite atomically replaces the history file with the in-memory entries.
+func (m *Manager) rewrite() error {
+	var buf bytes.Buffer
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		line, err := json.Marshal(m.entries[i])
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return safefile.WriteFile(m.filePath, buf.Bytes(), 0600)
+}
+
+// reload replaces the in-memory entries with the content of the history
+// file. A missing file is an empty history. Unreadable lines, such as one cut
+// short by a crash, are dropped: the file is rewritten without them, the
+// original is kept aside and the repair is reported by Recovered.
+func (m *Manager) reload() error {
+	err := m.load()
+	var pathErr *fs.PathError
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, os.ErrNotExist):
+		m.entries = nil
+		return nil
+	case errors.As(err, &pathErr):
+		return err
+	}
+
+	m.recovered = safefile.MoveAside(m.filePath, err)
+	var lines *lineError
+	if errors.As(err, &lines) {
+		m.recovered.Dropped = lines.count
+	}
+	if m.recovered.Backup == "" {
+		return nil
+	}
+	return m.rewrite()
+}
+
+// lineError reports unreadable lines in the history file.
+type lineError struct {
+	count int
+	first error
+}
+
+func (e *lineError) Error() string {
+	return fmt.Sprintf("%d unreadable line(s): %v", e.count, e.first)
+}
+
+// load reads the history file into m.entries. Lines that cannot be parsed
+// are skipped and reported as a *lineError once the rest has been read.
+func (m *Manager) load() error {
+	data, err := os.ReadFile(m.filePath)
+	if err != nil {
+		return err
+	}
+
+	m.entries = nil
+	var bad *lineError
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 64*1024), len(data)+1)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			if bad == nil {
+				bad = &lineError{first: err}
+			}
+			bad.count++
+			continue
+		}
+		m.entries = append(m.entries, e)
+	}
+	slices.Reverse(m.entries)
+
+	if bad != nil {
+		return bad
+	}
+	return nil
+}
+
+// migrate converts the history.json array written by earlier versions (most
+// recent first) into the history file, unless the latter already exists.
+// The old file is renamed to history.json.migrated.
+func (m *Manager) migrate(legacy string) error {
+	if _, err := os.Stat(m.filePath); err == nil {
+		return nil
+	}
+	data, err := os.ReadFile(legacy)
+	if err != nil {
+		return err
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		m.recovered = safefile.MoveAside(legacy, err)
+		return err
+	}
+	m.entries = entries
+	if err := m.rewrite(); err != nil {
+		return err
+	}
+	return os.Rename(legacy, legacy+".migrated")
+}
@@ -49,6 +49,16 @@ type Messages struct {
 	FallbackTitle     string
 	FallbackPrompt    string
 	FallbackInvalid   string
+	SelectedCount     string // format: "✓ %d selected"
+	RecentGroup       string
+	AllTargetsGroup   string
+	OrderTitle        string
+	OrderHelp         string
+	PreviewTitle      string // format: "Dry run: make -n %s"
+	PreviewHelp       string
+	PreviewMore       string // format: "… %d more lines"
+	PreviewEmpty      string
+	PreviewError      string // format: "✗ make -n failed: %v"
 
 	// config.go — key scheme
 	ConfigTitle            string
@@ -81,11 +91,113 @@ type Messages struct {
 	ConfigColorChoice           string
 	ConfigColorConfirm          string
 	ConfigColorInvalid          string
+	ConfigColorPreview          string
+	ConfigVisionNormal          string
+	ConfigVisionDeuteranopia    string
+	ConfigVisionProtanopia      string
+	ConfigVisionTritanopia      string
+	SetupHelpFmt                string // format: "%s navigate  •  enter choose  •  %s" (keys, quit)
+	SetupHelpBackFmt            string // format: "%s navigate  •  enter choose  •  ← back  •  %s" (keys, quit)
+	SetupStepFmt                string // format: "Step %d of %d"
+
+	// parallel.go
+	ErrParallelCount  string
+	ExecutingParallel string // format: "▶ Running in parallel (%d at a time): %s"
+	ParallelSummary   string
+	ParallelFailedLog string // format: "── %s (exit %d) ──"
+
+	// watch.go
+	ErrWatchUsage string
+	WatchHeader   string // format: "👀 Watching %s — Ctrl+C to stop"
+	WatchChanged  string // format: "↻ Changed: %s"
+	WatchWaiting  string
+	ErrExitCode   string // format: "✗ Failed with exit code %d"
+	Interrupted   string // format: "⚠ Interrupted by %s (exit code %d)"
+
+	// main.go — dry run
+	DryRunConfirm string
+
+	// logs.go
+	LogSaved    string // format: "📄 Full log: %s"
+	ErrNoLogs   string
+	ErrNoLogFor string // format: "✗ No run log for '%s' in this project."
+
+	// notify.go
+	NotifySuccess     string // format: "✓ %s completed in %s"
+	NotifyFailed      string // format: "✗ %s failed after %s (exit code %d)"
+	NotifyInterrupted string // format: "⚠ %s interrupted after %s"
+
+	// rerun.go
+	ErrLastUsage string // format: "✗ Invalid run number: %s (usage: mk --last [N])"
+	ErrNoLastRun string
+	ErrLastRange string // format: "✗ Only %d previous run(s) in this directory."
+	Rerunning    string // format: "↻ Re-running %s (recorded %s)"
+
+	// list.go
+	ListHelpFmt       string // format: "%s navigate  •  / filter  •  %s  •  %s" (keys, actions, quit)
+	ListCount         string // format: "(%d/%d)"
+	NoMatchingEntries string
+	ErrDeleteEntry    string // format: "✗ Unable to delete entry: %v"
+
+	// main.go — history picker
+	HistoryActions    string
+	ErrHistoryDirGone string // format: "✗ Directory no longer exists: %s"
+
+	// main.go — file recovery
+	WarnCorruptFile     string // format: "⚠ %s was unreadable and has been reset (old content kept in %s)"
+	WarnCorruptFileLost string // format: "⚠ %s was unreadable and has been reset"
+	WarnCorruptLines    string // format: "⚠ %d unreadable line(s) dropped from %s (old content kept in %s)"
+
+	// stats.go
+	StatsTitle          string // format: "📊 Run statistics — %s"
+	StatsEmpty          string
+	ErrStatsUsage       string // format: "✗ Unknown option: %s (usage: mk --stats [--all] [--json])"
+	StatsColTarget      string
+	StatsColRuns        string
+	StatsColSuccess     string
+	StatsColMedian      string
+	StatsColP90         string
+	StatsColLastFailure string
+	StatsColTrend       string
+
+	// history.go
+	ErrHistoryUsage       string // format: "✗ Unknown history command: %s (usage: mk --history [export|import|clear])"
+	ErrHistoryExportUsage string // format: "✗ Invalid option: %s (usage: mk --history export [--format json|csv] [-o FILE] [--project [DIR]] [--since DATE] [--until DATE] [--status STATUS])"
+	ErrHistoryImportUsage string
+	ErrHistoryClearUsage  string // format: "✗ Invalid option: %s (usage: mk --history clear [--project [DIR]] [--since DATE] [--until DATE] [--status STATUS] [--pruned] [--yes])"
+	HistoryClearConfirm   string // format: "Delete all %d history entries? [y/N] "
+	HistoryCleared        string // format: "✓ %d entries removed from the history"
+	ErrHistoryClear       string // format: "✗ Unable to clear history: %v"
+	ErrRerunRedacted      string // format: "✗ Arguments of this run were redacted and cannot be repeated: %s"
+	ErrInvalidDate        string // format: "✗ Invalid date: %s (expected YYYY-MM-DD or RFC 3339)"
+	ErrInvalidStatus      string // format: "✗ Invalid status: %s (success, failed or interrupted)"
+	ErrInvalidFormat      string // format: "✗ Invalid format: %s (json or csv)"
+	ErrHistoryExport      string // format: "✗ Unable to export history: %v"
+	ErrHistoryImport      string // format: "✗ Unable to import history: %v"
+	HistoryExported       string // format: "✓ %d entries exported to %s"
+	HistoryImported       string // format: "✓ %d entries imported (%d already present or invalid)"
+
+	// config.go
+	ConfigShowTitle       string
+	ConfigSourceDefault   string
+	WarnConfigIgnored     string // format: "⚠ Ignored: %v"
+	ErrConfigUsage        string
+	ErrUnknownSetting     string // format: "✗ Unknown setting: %s."
+	HintDidYouMean        string // format: "Did you mean %s?"
+	HintConfigList        string
+	ErrSettingChoice      string // format: "✗ Invalid value %q for %s (expected: %s)"
+	ErrSettingValue       string // format: "✗ Invalid value %q for %s: %v"
+	ConfigSet             string // format: "✓ %s = %s"
+	ConfigResetKey        string // format: "✓ %s reset to its default"
+	ConfigResetConfirm    string // format: "Reset every setting in %s? [y/N] "
+	ConfigResetAll        string
+	WarnSettingOverridden string // format: "⚠ %s is overridden here by %s"
 }
 
 var (
-	current *Messages
-	mu      sync.RWMutex
+	current     *Messages
+	currentLang = LangEN
+	mu          sync.RWMutex
 )
 
 func init() {
@@ -96,6 +208,7 @@ func init() {
 func Set(lang Lang) {
 	mu.Lock()
 	defer mu.Unlock()
+	currentLang = lang
 	switch lang {
 	case LangFR:
 		current = &messagesFR
@@ -104,10 +217,17 @@ func Set(lang Lang) {
 	case LangDE:
 		current = &messagesDE
 	default:
-		current = &messagesEN
+		current, currentLang = &messagesEN, LangEN
 	}
 }
 
+// Current returns the active language.
+func Current() Lang {
+	mu.RLock()
+	defer mu.RUnlock()
+	return currentLang
+}
+
 // Get returns the active Messages.
 func Get() *Messages {
 	mu.RLock()
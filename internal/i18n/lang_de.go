@@ -30,12 +30,22 @@ var messagesDE = Messages{
 	FilterActiveLabel: "Aktiver Filter: ",
 	NoMatchingTargets: "(keine passenden Ziele)",
 	TargetCount:       "(%d/%d Ziele)",
-	HelpArrows:        "↑/↓ navigieren  •  / filtern  •  Enter auswählen  •  q beenden",
-	HelpWASD:          "↑/↓/w/s navigieren  •  / filtern  •  Enter auswählen  •  q beenden",
-	HelpCustomFmt:     "↑/↓/%s/%s navigieren  •  / filtern  •  Enter auswählen  •  %s",
+	HelpArrows:        "↑/↓ navigieren  •  / filtern  •  Tab Probelauf  •  Leertaste auswählen  •  Enter ausführen  •  q beenden",
+	HelpWASD:          "↑/↓/w/s navigieren  •  / filtern  •  Tab Probelauf  •  Leertaste auswählen  •  Enter ausführen  •  q beenden",
+	HelpCustomFmt:     "↑/↓/%s/%s navigieren  •  / filtern  •  Tab Probelauf  •  Leertaste auswählen  •  Enter ausführen  •  %s",
 	FallbackTitle:     "🔨  Verfügbare Ziele:",
-	FallbackPrompt:    "Zielnummer (oder q zum Beenden): ",
+	FallbackPrompt:    "Zielnummer(n), z. B. 2 oder 3 1 (oder q zum Beenden): ",
 	FallbackInvalid:   "Ungültige Auswahl. Nummer zwischen 1 und %d (oder q): ",
+	SelectedCount:     "✓ %d ausgewählt",
+	RecentGroup:       "Zuletzt verwendet",
+	AllTargetsGroup:   "Alle Ziele",
+	OrderTitle:        "🔨  Ausführungsreihenfolge",
+	OrderHelp:         "↑/↓ navigieren  •  Leertaste greifen/ablegen  •  Enter ausführen  •  Esc zurück  •  q beenden",
+	PreviewTitle:      "Probelauf: make -n %s",
+	PreviewHelp:       "Enter wirklich ausführen  •  Esc abbrechen",
+	PreviewMore:       "… %d weitere Zeilen",
+	PreviewEmpty:      "(keine auszuführenden Befehle)",
+	PreviewError:      "✗ make -n fehlgeschlagen: %v",
 
 	// config.go — key scheme
 	ConfigTitle:            "⚙  mk-Konfiguration",
@@ -68,4 +78,105 @@ var messagesDE = Messages{
 	ConfigColorChoice:           "Auswahl [1/2/3/4] (Standard: 1): ",
 	ConfigColorConfirm:          "✓ Farben: %s",
 	ConfigColorInvalid:          "Ungültige Auswahl. Gib 1, 2, 3 oder 4 ein: ",
+	ConfigColorPreview:          "Gesehen mit:",
+	ConfigVisionNormal:          "Normale Sicht",
+	ConfigVisionDeuteranopia:    "Deuteranopie",
+	ConfigVisionProtanopia:      "Protanopie",
+	ConfigVisionTritanopia:      "Tritanopie",
+	SetupHelpFmt:                "%s navigieren  •  Enter wählen  •  %s",
+	SetupHelpBackFmt:            "%s navigieren  •  Enter wählen  •  ← zurück  •  %s",
+	SetupStepFmt:                "Schritt %d von %d",
+
+	// parallel.go
+	ErrParallelCount:  "✗ --parallel erwartet eine positive Anzahl von Jobs, z. B. mk --parallel 3 lint test docs",
+	ExecutingParallel: "▶ Parallele Ausführung (%d gleichzeitig): %s",
+	ParallelSummary:   "Zusammenfassung:",
+	ParallelFailedLog: "── %s (Exit-Code %d) ──",
+
+	// watch.go
+	ErrWatchUsage: "✗ Verwendung: mk --watch <Ziel> [glob...]",
+	WatchHeader:   "👀 Überwache %s — Strg+C zum Beenden",
+	WatchChanged:  "↻ Geändert: %s",
+	WatchWaiting:  "Warte auf Änderungen…",
+	ErrExitCode:   "✗ Fehlgeschlagen mit Exit-Code %d",
+	Interrupted:   "⚠ Unterbrochen durch %s (Exit-Code %d)",
+
+	// main.go — dry run
+	DryRunConfirm: "Wirklich ausführen? [j/N] ",
+
+	// logs.go
+	LogSaved:    "📄 Vollständiges Protokoll: %s",
+	ErrNoLogs:   "✗ Noch keine Ausführungsprotokolle für dieses Projekt.",
+	ErrNoLogFor: "✗ Kein Ausführungsprotokoll für '%s' in diesem Projekt.",
+
+	// notify.go
+	NotifySuccess:     "✓ %s abgeschlossen in %s",
+	NotifyFailed:      "✗ %s fehlgeschlagen nach %s (Exit-Code %d)",
+	NotifyInterrupted: "⚠ %s unterbrochen nach %s",
+
+	// rerun.go
+	ErrLastUsage: "✗ Ungültige Laufnummer: %s (Verwendung: mk --last [N])",
+	ErrNoLastRun: "✗ Kein vorheriger Lauf in diesem Verzeichnis.",
+	ErrLastRange: "✗ Nur %d vorherige(r) Lauf/Läufe in diesem Verzeichnis.",
+	Rerunning:    "↻ Wiederholung von %s (aufgezeichnet %s)",
+
+	// list.go
+	ListHelpFmt:       "%s navigieren  •  / filtern  •  %s  •  %s",
+	ListCount:         "(%d/%d)",
+	NoMatchingEntries: "(keine passenden Einträge)",
+	ErrDeleteEntry:    "✗ Eintrag kann nicht gelöscht werden: %v",
+
+	// main.go — history picker
+	HistoryActions:    "enter wiederholen  •  entf/x löschen",
+	ErrHistoryDirGone: "✗ Verzeichnis existiert nicht mehr: %s",
+
+	// main.go — file recovery
+	WarnCorruptFile:     "⚠ %s war unlesbar und wurde zurückgesetzt (alter Inhalt in %s gesichert)",
+	WarnCorruptFileLost: "⚠ %s war unlesbar und wurde zurückgesetzt",
+	WarnCorruptLines:    "⚠ %d unlesbare Zeile(n) aus %s entfernt (alter Inhalt in %s gesichert)",
+
+	// stats.go
+	StatsTitle:          "📊 Laufstatistik — %s",
+	StatsEmpty:          "Keine Läufe in diesem Verzeichnis aufgezeichnet.",
+	ErrStatsUsage:       "✗ Unbekannte Option: %s (Verwendung: mk --stats [--all] [--json])",
+	StatsColTarget:      "Ziel",
+	StatsColRuns:        "Läufe",
+	StatsColSuccess:     "Erfolg",
+	StatsColMedian:      "Median",
+	StatsColP90:         "p90",
+	StatsColLastFailure: "Letzter Fehler",
+	StatsColTrend:       "Letzte 8 Wochen",
+
+	// history.go
+	ErrHistoryUsage:       "✗ Unbekannter Verlaufsbefehl: %s (Verwendung: mk --history [export|import|clear])",
+	ErrHistoryExportUsage: "✗ Ungültige Option: %s (Verwendung: mk --history export [--format json|csv] [-o DATEI] [--project [VERZ]] [--since DATUM] [--until DATUM] [--status STATUS])",
+	ErrHistoryImportUsage: "✗ Verwendung: mk --history import DATEI",
+	ErrHistoryClearUsage:  "✗ Ungültige Option: %s (Verwendung: mk --history clear [--project [VERZ]] [--since DATUM] [--until DATUM] [--status STATUS] [--pruned] [--yes])",
+	HistoryClearConfirm:   "Alle %d Verlaufseinträge löschen? [j/N] ",
+	HistoryCleared:        "✓ %d Einträge aus dem Verlauf entfernt",
+	ErrHistoryClear:       "✗ Verlauf kann nicht gelöscht werden: %v",
+	ErrRerunRedacted:      "✗ Die Argumente dieses Laufs wurden geschwärzt, er kann nicht wiederholt werden: %s",
+	ErrInvalidDate:        "✗ Ungültiges Datum: %s (erwartet JJJJ-MM-TT oder RFC 3339)",
+	ErrInvalidStatus:      "✗ Ungültiger Status: %s (success, failed oder interrupted)",
+	ErrInvalidFormat:      "✗ Ungültiges Format: %s (json oder csv)",
+	ErrHistoryExport:      "✗ Verlauf kann nicht exportiert werden: %v",
+	ErrHistoryImport:      "✗ Verlauf kann nicht importiert werden: %v",
+	HistoryExported:       "✓ %d Einträge nach %s exportiert",
+	HistoryImported:       "✓ %d Einträge importiert (%d bereits vorhanden oder ungültig)",
+
+	// config.go
+	ConfigShowTitle:       "⚙  mk — wirksame Konfiguration",
+	ConfigSourceDefault:   "Standard",
+	WarnConfigIgnored:     "⚠ Ignoriert: %v",
+	ErrConfigUsage:        "✗ Verwendung: mk config [get <Schlüssel> | set <Schlüssel> <Wert> | list | reset [<Schlüssel>]]",
+	ErrUnknownSetting:     "✗ Unbekannte Einstellung: %s.",
+	HintDidYouMean:        "Meinten Sie %s?",
+	HintConfigList:        "`mk config list` zeigt alle Einstellungen.",
+	ErrSettingChoice:      "✗ Ungültiger Wert %q für %s (erwartet: %s)",
+	ErrSettingValue:       "✗ Ungültiger Wert %q für %s: %v",
+	ConfigSet:             "✓ %s = %s",
+	ConfigResetKey:        "✓ %s auf den Standardwert zurückgesetzt",
+	ConfigResetConfirm:    "Alle Einstellungen in %s zurücksetzen? [j/N] ",
+	ConfigResetAll:        "✓ Alle Einstellungen wurden zurückgesetzt",
+	WarnSettingOverridden: "⚠ %s wird hier durch %s überschrieben",
 }
@@ -30,12 +30,22 @@ var messagesEN = Messages{
 	FilterActiveLabel: "Active filter: ",
 	NoMatchingTargets: "(no matching targets)",
 	TargetCount:       "(%d/%d targets)",
-	HelpArrows:        "↑/↓ navigate  •  / filter  •  enter select  •  q quit",
-	HelpWASD:          "↑/↓/w/s navigate  •  / filter  •  enter select  •  q quit",
-	HelpCustomFmt:     "↑/↓/%s/%s navigate  •  / filter  •  enter select  •  %s",
+	HelpArrows:        "↑/↓ navigate  •  / filter  •  tab dry-run  •  space select  •  enter run  •  q quit",
+	HelpWASD:          "↑/↓/w/s navigate  •  / filter  •  tab dry-run  •  space select  •  enter run  •  q quit",
+	HelpCustomFmt:     "↑/↓/%s/%s navigate  •  / filter  •  tab dry-run  •  space select  •  enter run  •  %s",
 	FallbackTitle:     "🔨  Available targets:",
-	FallbackPrompt:    "Target number (or q to quit): ",
+	FallbackPrompt:    "Target number(s), e.g. 2 or 3 1 (or q to quit): ",
 	FallbackInvalid:   "Invalid choice. Number between 1 and %d (or q): ",
+	SelectedCount:     "✓ %d selected",
+	RecentGroup:       "Recently used",
+	AllTargetsGroup:   "All targets",
+	OrderTitle:        "🔨  Run order",
+	OrderHelp:         "↑/↓ navigate  •  space grab/drop  •  enter run  •  esc back  •  q quit",
+	PreviewTitle:      "Dry run: make -n %s",
+	PreviewHelp:       "enter run for real  •  esc cancel",
+	PreviewMore:       "… %d more lines",
+	PreviewEmpty:      "(no commands to run)",
+	PreviewError:      "✗ make -n failed: %v",
 
 	// config.go — key scheme
 	ConfigTitle:            "⚙  mk configuration",
@@ -68,4 +78,105 @@ var messagesEN = Messages{
 	ConfigColorChoice:           "Choice [1/2/3/4] (default: 1): ",
 	ConfigColorConfirm:          "✓ Colors: %s",
 	ConfigColorInvalid:          "Invalid choice. Enter 1, 2, 3 or 4: ",
+	ConfigColorPreview:          "As seen with:",
+	ConfigVisionNormal:          "Normal vision",
+	ConfigVisionDeuteranopia:    "Deuteranopia",
+	ConfigVisionProtanopia:      "Protanopia",
+	ConfigVisionTritanopia:      "Tritanopia",
+	SetupHelpFmt:                "%s navigate  •  enter choose  •  %s",
+	SetupHelpBackFmt:            "%s navigate  •  enter choose  •  ← back  •  %s",
+	SetupStepFmt:                "Step %d of %d",
+
+	// parallel.go
+	ErrParallelCount:  "✗ --parallel expects a positive number of jobs, e.g. mk --parallel 3 lint test docs",
+	ExecutingParallel: "▶ Running in parallel (%d at a time): %s",
+	ParallelSummary:   "Summary:",
+	ParallelFailedLog: "── %s (exit %d) ──",
+
+	// watch.go
+	ErrWatchUsage: "✗ Usage: mk --watch <target> [glob...]",
+	WatchHeader:   "👀 Watching %s — Ctrl+C to stop",
+	WatchChanged:  "↻ Changed: %s",
+	WatchWaiting:  "Waiting for changes…",
+	ErrExitCode:   "✗ Failed with exit code %d",
+	Interrupted:   "⚠ Interrupted by %s (exit code %d)",
+
+	// main.go — dry run
+	DryRunConfirm: "Run it for real? [y/N] ",
+
+	// logs.go
+	LogSaved:    "📄 Full log: %s",
+	ErrNoLogs:   "✗ No run logs for this project yet.",
+	ErrNoLogFor: "✗ No run log for '%s' in this project.",
+
+	// notify.go
+	NotifySuccess:     "✓ %s completed in %s",
+	NotifyFailed:      "✗ %s failed after %s (exit code %d)",
+	NotifyInterrupted: "⚠ %s interrupted after %s",
+
+	// rerun.go
+	ErrLastUsage: "✗ Invalid run number: %s (usage: mk --last [N])",
+	ErrNoLastRun: "✗ No previous run in this directory.",
+	ErrLastRange: "✗ Only %d previous run(s) in this directory.",
+	Rerunning:    "↻ Re-running %s (recorded %s)",
+
+	// list.go
+	ListHelpFmt:       "%s navigate  •  / filter  •  %s  •  %s",
+	ListCount:         "(%d/%d)",
+	NoMatchingEntries: "(no matching entries)",
+	ErrDeleteEntry:    "✗ Unable to delete entry: %v",
+
+	// main.go — history picker
+	HistoryActions:    "enter re-run  •  del/x delete",
+	ErrHistoryDirGone: "✗ Directory no longer exists: %s",
+
+	// main.go — file recovery
+	WarnCorruptFile:     "⚠ %s was unreadable and has been reset (old content kept in %s)",
+	WarnCorruptFileLost: "⚠ %s was unreadable and has been reset",
+	WarnCorruptLines:    "⚠ %d unreadable line(s) dropped from %s (old content kept in %s)",
+
+	// stats.go
+	StatsTitle:          "📊 Run statistics — %s",
+	StatsEmpty:          "No runs recorded in this directory.",
+	ErrStatsUsage:       "✗ Unknown option: %s (usage: mk --stats [--all] [--json])",
+	StatsColTarget:      "Target",
+	StatsColRuns:        "Runs",
+	StatsColSuccess:     "Success",
+	StatsColMedian:      "Median",
+	StatsColP90:         "p90",
+	StatsColLastFailure: "Last failure",
+	StatsColTrend:       "Last 8 weeks",
+
+	// history.go
+	ErrHistoryUsage:       "✗ Unknown history command: %s (usage: mk --history [export|import|clear])",
+	ErrHistoryExportUsage: "✗ Invalid option: %s (usage: mk --history export [--format json|csv] [-o FILE] [--project [DIR]] [--since DATE] [--until DATE] [--status STATUS])",
+	ErrHistoryImportUsage: "✗ Usage: mk --history import FILE",
+	ErrHistoryClearUsage:  "✗ Invalid option: %s (usage: mk --history clear [--project [DIR]] [--since DATE] [--until DATE] [--status STATUS] [--pruned] [--yes])",
+	HistoryClearConfirm:   "Delete all %d history entries? [y/N] ",
+	HistoryCleared:        "✓ %d entries removed from the history",
+	ErrHistoryClear:       "✗ Unable to clear history: %v",
+	ErrRerunRedacted:      "✗ Arguments of this run were redacted and cannot be repeated: %s",
+	ErrInvalidDate:        "✗ Invalid date: %s (expected YYYY-MM-DD or RFC 3339)",
+	ErrInvalidStatus:      "✗ Invalid status: %s (success, failed or interrupted)",
+	ErrInvalidFormat:      "✗ Invalid format: %s (json or csv)",
+	ErrHistoryExport:      "✗ Unable to export history: %v",
+	ErrHistoryImport:      "✗ Unable to import history: %v",
+	HistoryExported:       "✓ %d entries exported to %s",
+	HistoryImported:       "✓ %d entries imported (%d already present or invalid)",
+
+	// config.go
+	ConfigShowTitle:       "⚙  mk — effective configuration",
+	ConfigSourceDefault:   "default",
+	WarnConfigIgnored:     "⚠ Ignored: %v",
+	ErrConfigUsage:        "✗ Usage: mk config [get <key> | set <key> <value> | list | reset [<key>]]",
+	ErrUnknownSetting:     "✗ Unknown setting: %s.",
+	HintDidYouMean:        "Did you mean %s?",
+	HintConfigList:        "`mk config list` shows every setting.",
+	ErrSettingChoice:      "✗ Invalid value %q for %s (expected: %s)",
+	ErrSettingValue:       "✗ Invalid value %q for %s: %v",
+	ConfigSet:             "✓ %s = %s",
+	ConfigResetKey:        "✓ %s reset to its default",
+	ConfigResetConfirm:    "Reset every setting in %s? [y/N] ",
+	ConfigResetAll:        "✓ All settings reset to their defaults",
+	WarnSettingOverridden: "⚠ %s is overridden here by %s",
 }
@@ -30,12 +30,22 @@ var messagesES = Messages{
 	FilterActiveLabel: "Filtro activo: ",
 	NoMatchingTargets: "(ningún objetivo coincidente)",
 	TargetCount:       "(%d/%d objetivos)",
-	HelpArrows:        "↑/↓ navegar  •  / filtrar  •  enter seleccionar  •  q salir",
-	HelpWASD:          "↑/↓/w/s navegar  •  / filtrar  •  enter seleccionar  •  q salir",
-	HelpCustomFmt:     "↑/↓/%s/%s navegar  •  / filtrar  •  enter seleccionar  •  %s",
+	HelpArrows:        "↑/↓ navegar  •  / filtrar  •  tab simular  •  espacio seleccionar  •  enter ejecutar  •  q salir",
+	HelpWASD:          "↑/↓/w/s navegar  •  / filtrar  •  tab simular  •  espacio seleccionar  •  enter ejecutar  •  q salir",
+	HelpCustomFmt:     "↑/↓/%s/%s navegar  •  / filtrar  •  tab simular  •  espacio seleccionar  •  enter ejecutar  •  %s",
 	FallbackTitle:     "🔨  Objetivos disponibles:",
-	FallbackPrompt:    "Número del objetivo (o q para salir): ",
+	FallbackPrompt:    "Número(s) de objetivo, p. ej. 2 o 3 1 (o q para salir): ",
 	FallbackInvalid:   "Opción inválida. Número entre 1 y %d (o q): ",
+	SelectedCount:     "✓ %d seleccionado(s)",
+	RecentGroup:       "Usados recientemente",
+	AllTargetsGroup:   "Todos los objetivos",
+	OrderTitle:        "🔨  Orden de ejecución",
+	OrderHelp:         "↑/↓ navegar  •  espacio tomar/soltar  •  enter ejecutar  •  esc volver  •  q salir",
+	PreviewTitle:      "Simulación: make -n %s",
+	PreviewHelp:       "enter ejecutar de verdad  •  esc cancelar",
+	PreviewMore:       "… %d líneas más",
+	PreviewEmpty:      "(ningún comando que ejecutar)",
+	PreviewError:      "✗ make -n falló: %v",
 
 	// config.go — key scheme
 	ConfigTitle:            "⚙  Configuración de mk",
@@ -68,4 +78,105 @@ var messagesES = Messages{
 	ConfigColorChoice:           "Opción [1/2/3/4] (por defecto: 1): ",
 	ConfigColorConfirm:          "✓ Colores: %s",
 	ConfigColorInvalid:          "Opción inválida. Introduce 1, 2, 3 o 4: ",
+	ConfigColorPreview:          "Visto con:",
+	ConfigVisionNormal:          "Visión normal",
+	ConfigVisionDeuteranopia:    "Deuteranopía",
+	ConfigVisionProtanopia:      "Protanopía",
+	ConfigVisionTritanopia:      "Tritanopía",
+	SetupHelpFmt:                "%s navegar  •  enter elegir  •  %s",
+	SetupHelpBackFmt:            "%s navegar  •  enter elegir  •  ← volver  •  %s",
+	SetupStepFmt:                "Paso %d de %d",
+
+	// parallel.go
+	ErrParallelCount:  "✗ --parallel espera un número positivo de tareas, p. ej. mk --parallel 3 lint test docs",
+	ExecutingParallel: "▶ Ejecución en paralelo (%d a la vez): %s",
+	ParallelSummary:   "Resumen:",
+	ParallelFailedLog: "── %s (código %d) ──",
+
+	// watch.go
+	ErrWatchUsage: "✗ Uso: mk --watch <objetivo> [glob...]",
+	WatchHeader:   "👀 Vigilando %s — Ctrl+C para detener",
+	WatchChanged:  "↻ Modificado: %s",
+	WatchWaiting:  "Esperando cambios…",
+	ErrExitCode:   "✗ Falló con el código de salida %d",
+	Interrupted:   "⚠ Interrumpido por %s (código de salida %d)",
+
+	// main.go — dry run
+	DryRunConfirm: "¿Ejecutar de verdad? [s/N] ",
+
+	// logs.go
+	LogSaved:    "📄 Registro completo: %s",
+	ErrNoLogs:   "✗ Aún no hay registros de ejecución para este proyecto.",
+	ErrNoLogFor: "✗ No hay registro de ejecución para '%s' en este proyecto.",
+
+	// notify.go
+	NotifySuccess:     "✓ %s completado en %s",
+	NotifyFailed:      "✗ %s falló tras %s (código de salida %d)",
+	NotifyInterrupted: "⚠ %s interrumpido tras %s",
+
+	// rerun.go
+	ErrLastUsage: "✗ Número de ejecución no válido: %s (uso: mk --last [N])",
+	ErrNoLastRun: "✗ No hay ejecuciones anteriores en este directorio.",
+	ErrLastRange: "✗ Solo hay %d ejecución(es) anterior(es) en este directorio.",
+	Rerunning:    "↻ Repitiendo %s (registrada %s)",
+
+	// list.go
+	ListHelpFmt:       "%s navegar  •  / filtrar  •  %s  •  %s",
+	ListCount:         "(%d/%d)",
+	NoMatchingEntries: "(ninguna entrada coincide)",
+	ErrDeleteEntry:    "✗ No se puede eliminar la entrada: %v",
+
+	// main.go — history picker
+	HistoryActions:    "intro repetir  •  supr/x eliminar",
+	ErrHistoryDirGone: "✗ El directorio ya no existe: %s",
+
+	// main.go — file recovery
+	WarnCorruptFile:     "⚠ %s no se podía leer y se ha restablecido (contenido anterior guardado en %s)",
+	WarnCorruptFileLost: "⚠ %s no se podía leer y se ha restablecido",
+	WarnCorruptLines:    "⚠ %d línea(s) ilegible(s) eliminada(s) de %s (contenido anterior guardado en %s)",
+
+	// stats.go
+	StatsTitle:          "📊 Estadísticas de ejecución — %s",
+	StatsEmpty:          "No hay ejecuciones registradas en este directorio.",
+	ErrStatsUsage:       "✗ Opción desconocida: %s (uso: mk --stats [--all] [--json])",
+	StatsColTarget:      "Objetivo",
+	StatsColRuns:        "Ejec.",
+	StatsColSuccess:     "Éxito",
+	StatsColMedian:      "Mediana",
+	StatsColP90:         "p90",
+	StatsColLastFailure: "Último fallo",
+	StatsColTrend:       "Últimas 8 semanas",
+
+	// history.go
+	ErrHistoryUsage:       "✗ Comando de historial desconocido: %s (uso: mk --history [export|import|clear])",
+	ErrHistoryExportUsage: "✗ Opción no válida: %s (uso: mk --history export [--format json|csv] [-o ARCHIVO] [--project [DIR]] [--since FECHA] [--until FECHA] [--status ESTADO])",
+	ErrHistoryImportUsage: "✗ Uso: mk --history import ARCHIVO",
+	ErrHistoryClearUsage:  "✗ Opción no válida: %s (uso: mk --history clear [--project [DIR]] [--since FECHA] [--until FECHA] [--status ESTADO] [--pruned] [--yes])",
+	HistoryClearConfirm:   "¿Eliminar las %d entradas del historial? [s/N] ",
+	HistoryCleared:        "✓ %d entradas eliminadas del historial",
+	ErrHistoryClear:       "✗ No se puede borrar el historial: %v",
+	ErrRerunRedacted:      "✗ Los argumentos de esta ejecución se ocultaron y no se puede repetir: %s",
+	ErrInvalidDate:        "✗ Fecha no válida: %s (se espera AAAA-MM-DD o RFC 3339)",
+	ErrInvalidStatus:      "✗ Estado no válido: %s (success, failed o interrupted)",
+	ErrInvalidFormat:      "✗ Formato no válido: %s (json o csv)",
+	ErrHistoryExport:      "✗ No se puede exportar el historial: %v",
+	ErrHistoryImport:      "✗ No se puede importar el historial: %v",
+	HistoryExported:       "✓ %d entradas exportadas a %s",
+	HistoryImported:       "✓ %d entradas importadas (%d ya presentes o no válidas)",
+
+	// config.go
+	ConfigShowTitle:       "⚙  mk — configuración efectiva",
+	ConfigSourceDefault:   "predeterminado",
+	WarnConfigIgnored:     "⚠ Ignorado: %v",
+	ErrConfigUsage:        "✗ Uso: mk config [get <clave> | set <clave> <valor> | list | reset [<clave>]]",
+	ErrUnknownSetting:     "✗ Ajuste desconocido: %s.",
+	HintDidYouMean:        "¿Quiso decir %s?",
+	HintConfigList:        "`mk config list` muestra todos los ajustes.",
+	ErrSettingChoice:      "✗ Valor no válido %q para %s (se espera: %s)",
+	ErrSettingValue:       "✗ Valor no válido %q para %s: %v",
+	ConfigSet:             "✓ %s = %s",
+	ConfigResetKey:        "✓ %s restablecido a su valor predeterminado",
+	ConfigResetConfirm:    "¿Restablecer todos los ajustes de %s? [s/N] ",
+	ConfigResetAll:        "✓ Todos los ajustes se han restablecido",
+	WarnSettingOverridden: "⚠ %s está reemplazado aquí por %s",
 }
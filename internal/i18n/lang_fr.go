@@ -30,12 +30,22 @@ var messagesFR = Messages{
 	FilterActiveLabel: "Filtre actif: ",
 	NoMatchingTargets: "(aucune cible correspondante)",
 	TargetCount:       "(%d/%d cibles)",
-	HelpArrows:        "↑/↓ naviguer  •  / filtrer  •  enter sélectionner  •  q quitter",
-	HelpWASD:          "↑/↓/w/s naviguer  •  / filtrer  •  enter sélectionner  •  q quitter",
-	HelpCustomFmt:     "↑/↓/%s/%s naviguer  •  / filtrer  •  enter sélectionner  •  %s",
+	HelpArrows:        "↑/↓ naviguer  •  / filtrer  •  tab simuler  •  espace sélectionner  •  enter lancer  •  q quitter",
+	HelpWASD:          "↑/↓/w/s naviguer  •  / filtrer  •  tab simuler  •  espace sélectionner  •  enter lancer  •  q quitter",
+	HelpCustomFmt:     "↑/↓/%s/%s naviguer  •  / filtrer  •  tab simuler  •  espace sélectionner  •  enter lancer  •  %s",
 	FallbackTitle:     "🔨  Cibles disponibles :",
-	FallbackPrompt:    "Numéro de la cible (ou q pour quitter) : ",
+	FallbackPrompt:    "Numéro(s) de cible, ex. 2 ou 3 1 (ou q pour quitter) : ",
 	FallbackInvalid:   "Choix invalide. Numéro entre 1 et %d (ou q) : ",
+	SelectedCount:     "✓ %d sélectionnée(s)",
+	RecentGroup:       "Utilisées récemment",
+	AllTargetsGroup:   "Toutes les cibles",
+	OrderTitle:        "🔨  Ordre d'exécution",
+	OrderHelp:         "↑/↓ naviguer  •  espace saisir/déposer  •  enter lancer  •  échap retour  •  q quitter",
+	PreviewTitle:      "Simulation : make -n %s",
+	PreviewHelp:       "enter lancer pour de vrai  •  échap annuler",
+	PreviewMore:       "… %d lignes de plus",
+	PreviewEmpty:      "(aucune commande à exécuter)",
+	PreviewError:      "✗ make -n a échoué : %v",
 
 	// config.go — key scheme
 	ConfigTitle:            "⚙  Configuration de mk",
@@ -68,4 +78,105 @@ var messagesFR = Messages{
 	ConfigColorChoice:           "Choix [1/2/3/4] (défaut: 1) : ",
 	ConfigColorConfirm:          "✓ Couleurs : %s",
 	ConfigColorInvalid:          "Choix invalide. Entre 1, 2, 3 ou 4 : ",
+	ConfigColorPreview:          "Vu avec :",
+	ConfigVisionNormal:          "Vision normale",
+	ConfigVisionDeuteranopia:    "Deutéranopie",
+	ConfigVisionProtanopia:      "Protanopie",
+	ConfigVisionTritanopia:      "Tritanopie",
+	SetupHelpFmt:                "%s naviguer  •  enter choisir  •  %s",
+	SetupHelpBackFmt:            "%s naviguer  •  enter choisir  •  ← retour  •  %s",
+	SetupStepFmt:                "Étape %d sur %d",
+
+	// parallel.go
+	ErrParallelCount:  "✗ --parallel attend un nombre de tâches positif, ex. mk --parallel 3 lint test docs",
+	ExecutingParallel: "▶ Exécution en parallèle (%d à la fois) : %s",
+	ParallelSummary:   "Résumé :",
+	ParallelFailedLog: "── %s (code %d) ──",
+
+	// watch.go
+	ErrWatchUsage: "✗ Utilisation : mk --watch <cible> [glob...]",
+	WatchHeader:   "👀 Surveillance de %s — Ctrl+C pour arrêter",
+	WatchChanged:  "↻ Modifié : %s",
+	WatchWaiting:  "En attente de modifications…",
+	ErrExitCode:   "✗ Échec avec le code de sortie %d",
+	Interrupted:   "⚠ Interrompu par %s (code de sortie %d)",
+
+	// main.go — dry run
+	DryRunConfirm: "Lancer pour de vrai ? [o/N] ",
+
+	// logs.go
+	LogSaved:    "📄 Journal complet : %s",
+	ErrNoLogs:   "✗ Aucun journal d'exécution pour ce projet.",
+	ErrNoLogFor: "✗ Aucun journal d'exécution pour '%s' dans ce projet.",
+
+	// notify.go
+	NotifySuccess:     "✓ %s terminé en %s",
+	NotifyFailed:      "✗ %s a échoué après %s (code de sortie %d)",
+	NotifyInterrupted: "⚠ %s interrompu après %s",
+
+	// rerun.go
+	ErrLastUsage: "✗ Numéro d'exécution invalide : %s (usage : mk --last [N])",
+	ErrNoLastRun: "✗ Aucune exécution précédente dans ce répertoire.",
+	ErrLastRange: "✗ Seulement %d exécution(s) précédente(s) dans ce répertoire.",
+	Rerunning:    "↻ Relance de %s (enregistrée %s)",
+
+	// list.go
+	ListHelpFmt:       "%s naviguer  •  / filtrer  •  %s  •  %s",
+	ListCount:         "(%d/%d)",
+	NoMatchingEntries: "(aucune entrée correspondante)",
+	ErrDeleteEntry:    "✗ Impossible de supprimer l'entrée : %v",
+
+	// main.go — history picker
+	HistoryActions:    "entrée relancer  •  suppr/x supprimer",
+	ErrHistoryDirGone: "✗ Le répertoire n'existe plus : %s",
+
+	// main.go — file recovery
+	WarnCorruptFile:     "⚠ %s était illisible et a été réinitialisé (ancien contenu conservé dans %s)",
+	WarnCorruptFileLost: "⚠ %s était illisible et a été réinitialisé",
+	WarnCorruptLines:    "⚠ %d ligne(s) illisible(s) retirée(s) de %s (ancien contenu conservé dans %s)",
+
+	// stats.go
+	StatsTitle:          "📊 Statistiques d'exécution — %s",
+	StatsEmpty:          "Aucune exécution enregistrée dans ce répertoire.",
+	ErrStatsUsage:       "✗ Option inconnue : %s (usage : mk --stats [--all] [--json])",
+	StatsColTarget:      "Cible",
+	StatsColRuns:        "Exéc.",
+	StatsColSuccess:     "Succès",
+	StatsColMedian:      "Médiane",
+	StatsColP90:         "p90",
+	StatsColLastFailure: "Dernier échec",
+	StatsColTrend:       "8 dernières semaines",
+
+	// history.go
+	ErrHistoryUsage:       "✗ Commande d'historique inconnue : %s (usage : mk --history [export|import|clear])",
+	ErrHistoryExportUsage: "✗ Option invalide : %s (usage : mk --history export [--format json|csv] [-o FICHIER] [--project [DOSSIER]] [--since DATE] [--until DATE] [--status STATUT])",
+	ErrHistoryImportUsage: "✗ Usage : mk --history import FICHIER",
+	ErrHistoryClearUsage:  "✗ Option invalide : %s (usage : mk --history clear [--project [DOSSIER]] [--since DATE] [--until DATE] [--status STATUT] [--pruned] [--yes])",
+	HistoryClearConfirm:   "Supprimer les %d entrées de l'historique ? [o/N] ",
+	HistoryCleared:        "✓ %d entrées supprimées de l'historique",
+	ErrHistoryClear:       "✗ Impossible d'effacer l'historique : %v",
+	ErrRerunRedacted:      "✗ Les arguments de cette exécution ont été masqués, elle ne peut pas être relancée : %s",
+	ErrInvalidDate:        "✗ Date invalide : %s (attendu AAAA-MM-JJ ou RFC 3339)",
+	ErrInvalidStatus:      "✗ Statut invalide : %s (success, failed ou interrupted)",
+	ErrInvalidFormat:      "✗ Format invalide : %s (json ou csv)",
+	ErrHistoryExport:      "✗ Impossible d'exporter l'historique : %v",
+	ErrHistoryImport:      "✗ Impossible d'importer l'historique : %v",
+	HistoryExported:       "✓ %d entrées exportées vers %s",
+	HistoryImported:       "✓ %d entrées importées (%d déjà présentes ou invalides)",
+
+	// config.go
+	ConfigShowTitle:       "⚙  mk — configuration effective",
+	ConfigSourceDefault:   "défaut",
+	WarnConfigIgnored:     "⚠ Ignoré : %v",
+	ErrConfigUsage:        "✗ Usage : mk config [get <clé> | set <clé> <valeur> | list | reset [<clé>]]",
+	ErrUnknownSetting:     "✗ Réglage inconnu : %s.",
+	HintDidYouMean:        "Vouliez-vous dire %s ?",
+	HintConfigList:        "`mk config list` affiche tous les réglages.",
+	ErrSettingChoice:      "✗ Valeur invalide %q pour %s (attendu : %s)",
+	ErrSettingValue:       "✗ Valeur invalide %q pour %s : %v",
+	ConfigSet:             "✓ %s = %s",
+	ConfigResetKey:        "✓ %s rétabli à sa valeur par défaut",
+	ConfigResetConfirm:    "Rétablir tous les réglages de %s ? [o/N] ",
+	ConfigResetAll:        "✓ Tous les réglages ont été rétablis",
+	WarnSettingOverridden: "⚠ %s est remplacé ici par %s",
 }
@@ -0,0 +1,129 @@
+package logs
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/subut0n/mk/internal/paths"
+)
+
+const (
+	DefaultKeep   = 10 // log files kept per target
+	DefaultMaxAge = 30 * 24 * time.Hour
+
+	timeLayout = "20060102-150405.000"
+)
+
+// fileName matches "<target>-<timestamp>.log".
+var fileName = regexp.MustCompile(`^(.+)-(\d{8}-\d{6}\.\d{3})\.log$`)
+
+// ProjectDir returns the log directory of the project rooted at dir:
+// <state dir>/logs/<name>-<hash>, where the hash keeps projects that share a
+// directory name apart.
+func ProjectDir(dir string) (string, error) {
+	state, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(state, "logs", ProjectID(dir)), nil
+}
+
+// ProjectID derives a stable, file-name-safe identifier from a project directory.
+func ProjectID(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	sum := sha1.Sum([]byte(dir))
+	return sanitize(filepath.Base(dir)) + "-" + hex.EncodeToString(sum[:4])
+}
+
+// Create opens a new log file for a run of target started at t.
+func Create(projectDir, target string, t time.Time) (*os.File, error) {
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("%s-%s.log", sanitize(target), t.Format(timeLayout))
+	return os.OpenFile(filepath.Join(projectDir, name), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+}
+
+// Latest returns the path of the most recent log of target in projectDir, or
+// of any target when target is empty.
+func Latest(projectDir, target string) (string, error) {
+	files, err := list(projectDir)
+	if err != nil {
+		return "", err
+	}
+	var best logFile
+	for _, f := range files {
+		if target != "" && f.target != sanitize(target) {
+			continue
+		}
+		if f.stamp > best.stamp {
+			best = f
+		}
+	}
+	if best.path == "" {
+		return "", os.ErrNotExist
+	}
+	return best.path, nil
+}
+
+// Prune removes logs older than maxAge and keeps at most keep logs per target.
+// A zero keep or maxAge disables the corresponding limit.
+func Prune(projectDir string, keep int, maxAge time.Duration) error {
+	files, err := list(projectDir)
+	if err != nil {
+		return err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].stamp > files[j].stamp })
+
+	cutoff := time.Now().Add(-maxAge).Format(timeLayout)
+	perTarget := map[string]int{}
+	for _, f := range files {
+		perTarget[f.target]++
+		if (keep > 0 && perTarget[f.target] > keep) || (maxAge > 0 && f.stamp < cutoff) {
+			_ = os.Remove(f.path)
+		}
+	}
+	return nil
+}
+
+type logFile struct {
+	path   string
+	target string
+	stamp  string // sortable timestamp from the file name
+}
+
+func list(projectDir string) ([]logFile, error) {
+	entries, err := os.ReadDir(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	var files []logFile
+	for _, e := range entries {
+		m := fileName.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		files = append(files, logFile{path: filepath.Join(projectDir, e.Name()), target: m[1], stamp: m[2]})
+	}
+	return files, nil
+}
+
+// sanitize makes a target or directory name safe to use in a file name.
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', ' ':
+			return '_'
+		}
+		return r
+	}, name)
+}
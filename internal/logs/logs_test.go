@@ -0,0 +1,102 @@
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestProjectIDIsStableAndDistinct(t *testing.T) {
+	a := ProjectID("/src/one/app")
+	if a != ProjectID("/src/one/app") {
+		t.Error("expected a stable project id")
+	}
+	if a == ProjectID("/src/two/app") {
+		t.Error("projects with the same name should get different ids")
+	}
+	if filepath.Base(a) != a {
+		t.Errorf("project id should be a single path element, got %q", a)
+	}
+}
+
+func TestCreateAndLatest(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local)
+
+	for i, target := range []string{"build", "test", "build"} {
+		f, err := Create(dir, target, base.Add(time.Duration(i)*time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString(target)
+		f.Close()
+	}
+
+	latest, err := Latest(dir, "build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(latest) != "build-20260102-030605.000.log" {
+		t.Errorf("unexpected latest build log %q", latest)
+	}
+
+	latest, err = Latest(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(latest) != "build-20260102-030605.000.log" {
+		t.Errorf("unexpected latest log %q", latest)
+	}
+
+	if _, err := Latest(dir, "deploy"); !os.IsNotExist(err) {
+		t.Errorf("expected not-exist error for unknown target, got %v", err)
+	}
+}
+
+func TestTargetWithSlash(t *testing.T) {
+	dir := t.TempDir()
+	f, err := Create(dir, "docs/html", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if _, err := Latest(dir, "docs/html"); err != nil {
+		t.Errorf("expected to find log of a target containing a slash: %v", err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	for i := 0; i < 5; i++ {
+		f, err := Create(dir, "build", now.Add(-time.Duration(i)*time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+	}
+	old, err := Create(dir, "test", now.Add(-48*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	old.Close()
+
+	if err := Prune(dir, 3, 24*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := list(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("expected 3 build logs to remain, got %d", len(files))
+	}
+	for _, f := range files {
+		if f.target != "build" {
+			t.Errorf("expected old test log to be pruned, found %s", f.path)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package notify
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// DefaultAfter is the run duration above which a notification is sent when
+// the configuration does not set one.
+const DefaultAfter = 30 * time.Second
+
+// OSC escape sequence flavors understood by terminal emulators.
+const (
+	OSC9    = "9"    // iTerm2, Windows Terminal, WezTerm, kitty, ...
+	OSC777  = "777"  // VTE-based terminals (GNOME Terminal, Tilix), foot, ...
+	OSCBoth = "both" // send both sequences
+	OSCOff  = "off"  // send none
+)
+
+// Options selects how a finished run is announced.
+type Options struct {
+	After   time.Duration // only runs lasting at least this long are announced
+	Bell    bool          // ring the terminal bell
+	OSC     string        // desktop notification escape sequence: OSC9, OSC777 or OSCBoth
+	Command string        // shell command run with the event's placeholders expanded
+}
+
+// Enabled reports whether any notification channel is configured.
+func (o Options) Enabled() bool {
+	return o.Bell || (o.OSC != "" && o.OSC != OSCOff) || o.Command != ""
+}
+
+// Due reports whether a run that lasted d should be announced.
+func (o Options) Due(d time.Duration) bool {
+	return o.Enabled() && d >= o.After
+}
+
+// Event describes a finished run.
+type Event struct {
+	Target   string
+	Status   string // success, failed or interrupted
+	Duration time.Duration
+	Message  string // human-readable summary shown by desktop notifications
+}
+
+// Send announces ev on every channel enabled in opts. Escape sequences are
+// written to term, which should be nil when it is not a terminal. Errors from
+// the notification command are returned; the command itself is not waited for.
+func Send(opts Options, ev Event, term io.Writer) error {
+	if term != nil {
+		var seq strings.Builder
+		if opts.Bell {
+			seq.WriteString("\a")
+		}
+		seq.WriteString(Escape(opts.OSC, "mk", ev.Message))
+		if seq.Len() > 0 {
+			io.WriteString(term, seq.String())
+		}
+	}
+	if opts.Command == "" {
+		return nil
+	}
+
+	cmd := shellCommand(Expand(opts.Command, ev))
+	cmd.Env = append(os.Environ(),
+		"MK_TARGET="+ev.Target,
+		"MK_STATUS="+ev.Status,
+		"MK_DURATION="+formatDuration(ev.Duration),
+	)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
+
+// Escape returns the desktop notification escape sequence(s) of the given
+// flavor, or "" for an unknown one. Inside tmux the sequences are wrapped in
+// a passthrough so that they reach the outer terminal.
+func Escape(flavor, title, body string) string {
+	title, body = sanitize(title), sanitize(body)
+	var seqs []string
+	switch flavor {
+	case OSC9:
+		seqs = []string{"\033]9;" + body + "\a"}
+	case OSC777:
+		seqs = []string{"\033]777;notify;" + title + ";" + body + "\a"}
+	case OSCBoth:
+		seqs = []string{"\033]9;" + body + "\a", "\033]777;notify;" + title + ";" + body + "\a"}
+	}
+	var b strings.Builder
+	for _, s := range seqs {
+		if os.Getenv("TMUX") != "" {
+			s = "\033Ptmux;" + strings.ReplaceAll(s, "\033", "\033\033") + "\033\\"
+		}
+		b.WriteString(s)
+	}
+	return b.String()
+}
+
+// Expand replaces the {target}, {status} and {duration} placeholders in a
+// notification command. Values are shell-quoted so that target names cannot
+// inject commands.
+func Expand(command string, ev Event) string {
+	return strings.NewReplacer(
+		"{target}", quote(ev.Target),
+		"{status}", quote(ev.Status),
+		"{duration}", quote(formatDuration(ev.Duration)),
+	).Replace(command)
+}
+
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
+
+// quote wraps s in single quotes for sh; Windows' cmd gets double quotes.
+func quote(s string) string {
+	if runtime.GOOS == "windows" {
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// sanitize strips control characters and the ';' separator, which would end
+// or split an escape sequence.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == ';' {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,107 @@
+package notify
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDue(t *testing.T) {
+	opts := Options{After: 10 * time.Second}
+	if opts.Due(time.Minute) {
+		t.Error("no channel enabled: nothing is due")
+	}
+	opts.OSC = OSCOff
+	if opts.Due(time.Minute) {
+		t.Error("escape sequences turned off: nothing is due")
+	}
+	opts.Bell = true
+	if opts.Due(9 * time.Second) {
+		t.Error("short runs are not announced")
+	}
+	if !opts.Due(10 * time.Second) {
+		t.Error("runs reaching the threshold are announced")
+	}
+}
+
+func TestEscape(t *testing.T) {
+	t.Setenv("TMUX", "")
+	cases := map[string]string{
+		OSC9:    "\033]9;build done\a",
+		OSC777:  "\033]777;notify;mk;build done\a",
+		OSCBoth: "\033]9;build done\a\033]777;notify;mk;build done\a",
+		OSCOff:  "",
+		"":      "",
+		"bogus": "",
+	}
+	for flavor, want := range cases {
+		if got := Escape(flavor, "mk", "build done"); got != want {
+			t.Errorf("Escape(%q) = %q, want %q", flavor, got, want)
+		}
+	}
+
+	if got := Escape(OSC777, "mk", "a;b\x1b\ac"); got != "\033]777;notify;mk;abc\a" {
+		t.Errorf("control characters not stripped: %q", got)
+	}
+}
+
+func TestEscapeTmux(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	want := "\033Ptmux;\033\033]9;done\a\033\\"
+	if got := Escape(OSC9, "mk", "done"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh quoting")
+	}
+	ev := Event{Target: "it's", Status: "failed", Duration: 83 * time.Second}
+	got := Expand("notify-send {target} {status} {duration}", ev)
+	want := `notify-send 'it'\''s' 'failed' '1m23s'`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestSend(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil || runtime.GOOS == "windows" {
+		t.Skip("sh not available")
+	}
+	t.Setenv("TMUX", "")
+	out := filepath.Join(t.TempDir(), "out")
+	opts := Options{
+		Bell:    true,
+		OSC:     OSC9,
+		Command: `printf '%s|%s|%s' "$MK_TARGET" {status} "$MK_DURATION" > ` + out,
+	}
+	var term bytes.Buffer
+	err := Send(opts, Event{Target: "release", Status: "success", Duration: 2 * time.Second, Message: "release done"}, &term)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := term.String(); got != "\a\033]9;release done\a" {
+		t.Errorf("unexpected escape sequences %q", got)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, _ := os.ReadFile(out)
+		if strings.HasSuffix(string(data), "2s") {
+			if string(data) != "release|success|2s" {
+				t.Errorf("unexpected command output %q", data)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("notification command did not run, got %q", data)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -8,8 +8,9 @@ import (
 
 // Target represents a Makefile target with its description.
 type Target struct {
-	Name        string
-	Description string
+	Name          string
+	Description   string
+	Prerequisites []string // normal prerequisites, as written (variables are not expanded)
 }
 
 // ParseMakefile reads a Makefile and extracts targets with their descriptions.
@@ -60,8 +61,9 @@ func ParseMakefile(path string) ([]Target, error) {
 			}
 
 			targets = append(targets, Target{
-				Name:        name,
-				Description: desc,
+				Name:          name,
+				Description:   desc,
+				Prerequisites: extractPrerequisites(line),
 			})
 			pendingDescription = ""
 			continue
@@ -110,3 +112,19 @@ func extractTargetName(line string) string {
 	}
 	return strings.TrimSpace(line[:colonIdx])
 }
+
+// extractPrerequisites returns the normal prerequisites listed on a target line,
+// stopping at an inline recipe (;), a ## description or order-only prerequisites (|).
+func extractPrerequisites(line string) []string {
+	colonIdx := strings.Index(line, ":")
+	if colonIdx <= 0 {
+		return nil
+	}
+	rest := strings.TrimPrefix(line[colonIdx+1:], ":") // double-colon rules
+	for _, stop := range []string{"##", ";", "|"} {
+		if idx := strings.Index(rest, stop); idx != -1 {
+			rest = rest[:idx]
+		}
+	}
+	return strings.Fields(rest)
+}
@@ -187,3 +187,36 @@ build:
 		t.Errorf("expected 'build', got %q", targets[0].Name)
 	}
 }
+
+func TestPrerequisites(t *testing.T) {
+	path := writeTempMakefile(t, `## Build
+build: main.go go.mod | dist ## Build the binary
+	go build .
+
+test:: build ; go test ./...
+
+clean:
+	rm -rf dist
+`)
+	targets, err := ParseMakefile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 3 {
+		t.Fatalf("expected 3 targets, got %d", len(targets))
+	}
+	expected := [][]string{{"main.go", "go.mod"}, {"build"}, nil}
+	for i, want := range expected {
+		got := targets[i].Prerequisites
+		if len(got) != len(want) {
+			t.Errorf("target %d: expected prerequisites %v, got %v", i, want, got)
+			continue
+		}
+		for j := range want {
+			if got[j] != want[j] {
+				t.Errorf("target %d: expected prerequisites %v, got %v", i, want, got)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// StateDir returns the directory where mk keeps state that is not
+// configuration (logs, history): $XDG_STATE_HOME/mk, ~/.local/state/mk, or
+// %LocalAppData%\mk on Windows. The directory is created if needed.
+func StateDir() (string, error) {
+	base := os.Getenv("XDG_STATE_HOME")
+	if base == "" {
+		if runtime.GOOS == "windows" {
+			base, _ = os.UserCacheDir()
+		} else if home, err := os.UserHomeDir(); err == nil {
+			base = filepath.Join(home, ".local", "state")
+		}
+	}
+	if base == "" {
+		base = os.TempDir()
+	}
+
+	dir := filepath.Join(base, "mk")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
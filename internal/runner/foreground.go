@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"time"
+)
+
+// Run executes job attached to the terminal: make reads mk's stdin and writes
+// to stdout and stderr. Make runs in its own process group; termination
+// signals received by mk are forwarded to that group, which is killed if it
+// is still running grace after the first signal.
+//
+// When mk runs in a terminal and the platform supports it, make is given a
+// pseudo-terminal of its own: its combined output is copied to stdout, with
+// colors preserved, while remaining interactive.
+func Run(job Job, stdout, stderr io.Writer, grace time.Duration) Result {
+	res, ok := runPTY(job, stdout, grace)
+	if !ok {
+		res = runAttached(job, stdout, stderr, grace)
+	}
+	if res.Signal != nil && res.ExitCode == 0 {
+		res.ExitCode = 128 + signalNumber(res.Signal)
+	}
+	return res
+}
+
+// runAttached runs job with mk's own standard streams.
+func runAttached(job Job, stdout, stderr io.Writer, grace time.Duration) Result {
+	cmd := job.Command()
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	restore := setForeground(cmd)
+	defer restore()
+
+	r := newRelay(grace)
+	p, err := startCmd(job, cmd, nil)
+	if err != nil {
+		r.stop()
+		return Result{Job: job, StartedAt: time.Now(), ExitCode: 127, Err: err}
+	}
+	r.add(p)
+	<-p.Done()
+	sig := r.stop()
+
+	res := p.Result()
+	if res.Signal == nil {
+		res.Signal = sig
+	}
+	return res
+}
+
+func signalNumber(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return int(s)
+	}
+	return 2
+}
@@ -0,0 +1,130 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/subut0n/mk/internal/ansi"
+)
+
+// RunParallel runs jobs as separate make processes, at most n at a time.
+// Each output line is written to out prefixed with a label colored from the
+// palette; the full output of every job is also kept in its Result.
+// Results are returned in the same order as jobs.
+func RunParallel(jobs []Job, n int, palette []string, out io.Writer) []Result {
+	if n < 1 {
+		n = 1
+	}
+
+	width := 0
+	for _, j := range jobs {
+		if len(j.Target) > width {
+			width = len(j.Target)
+		}
+	}
+
+	var mu sync.Mutex
+	results := make([]Result, len(jobs))
+	sem := make(chan struct{}, n)
+	var wg sync.WaitGroup
+	r := newRelay(DefaultGrace)
+
+	for i, job := range jobs {
+		sem <- struct{}{}
+		if sig := r.received(); sig != nil {
+			// Do not start queued jobs once mk has been asked to stop.
+			<-sem
+			results[i] = Result{Job: job, StartedAt: time.Now(), ExitCode: 128 + signalNumber(sig), Signal: sig}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, job Job) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			color := ""
+			if len(palette) > 0 {
+				color = palette[i%len(palette)]
+			}
+			label := fmt.Sprintf("%s%-*s%s %s│%s ", color, width, job.Target, ansi.Reset, ansi.Gray, ansi.Reset)
+			pw := &prefixWriter{mu: &mu, out: out, prefix: label}
+			var log bytes.Buffer
+			w := io.MultiWriter(pw, &log)
+
+			p, err := Start(job, w, w)
+			if err != nil {
+				results[i] = Result{Job: job, StartedAt: time.Now(), ExitCode: 127, Err: err}
+				return
+			}
+			r.add(p)
+			<-p.Done()
+			pw.Flush()
+
+			results[i] = p.Result()
+			results[i].Log = log.Bytes()
+		}(i, job)
+	}
+	wg.Wait()
+
+	if sig := r.stop(); sig != nil {
+		for i := range results {
+			if results[i].Signal == nil && results[i].ExitCode != 0 {
+				results[i].Signal = sig
+			}
+		}
+	}
+
+	return results
+}
+
+// ExitStatus combines parallel results into a single exit status: zero when
+// every job succeeded, otherwise the status of the first failed job.
+func ExitStatus(results []Result) int {
+	for _, r := range results {
+		if r.ExitCode != 0 {
+			return r.ExitCode
+		}
+	}
+	return 0
+}
+
+// prefixWriter writes complete lines to out, each preceded by prefix.
+// Writes from several prefixWriters sharing mu never interleave mid-line.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(w.buf[:i+1])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes any buffered partial line.
+func (w *prefixWriter) Flush() {
+	if len(w.buf) == 0 {
+		return
+	}
+	w.emit(append(w.buf, '\n'))
+	w.buf = nil
+}
+
+func (w *prefixWriter) emit(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	io.WriteString(w.out, w.prefix)
+	w.out.Write(line)
+}
@@ -0,0 +1,83 @@
+package runner
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func writeTempMakefile(t *testing.T, content string) string {
+	t.Helper()
+	if _, err := exec.LookPath("make"); err != nil {
+		t.Skip("make not available")
+	}
+	path := filepath.Join(t.TempDir(), "Makefile")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPrefixWriterCompleteLines(t *testing.T) {
+	var out bytes.Buffer
+	var mu sync.Mutex
+	w := &prefixWriter{mu: &mu, out: &out, prefix: "[a] "}
+
+	w.Write([]byte("one\ntw"))
+	w.Write([]byte("o\nthree"))
+	if got := out.String(); got != "[a] one\n[a] two\n" {
+		t.Errorf("unexpected output before flush: %q", got)
+	}
+
+	w.Flush()
+	if got := out.String(); got != "[a] one\n[a] two\n[a] three\n" {
+		t.Errorf("unexpected output after flush: %q", got)
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	if code := ExitStatus([]Result{{ExitCode: 0}, {ExitCode: 0}}); code != 0 {
+		t.Errorf("expected 0, got %d", code)
+	}
+	if code := ExitStatus([]Result{{ExitCode: 0}, {ExitCode: 3}, {ExitCode: 2}}); code != 3 {
+		t.Errorf("expected first failure 3, got %d", code)
+	}
+}
+
+func TestRunParallel(t *testing.T) {
+	path := writeTempMakefile(t, `ok:
+	@echo ok-output
+fail:
+	@echo fail-output
+	@exit 3
+`)
+
+	var out bytes.Buffer
+	results := RunParallel([]Job{
+		{Makefile: path, Target: "ok"},
+		{Makefile: path, Target: "fail"},
+	}, 2, nil, &out)
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].ExitCode != 0 {
+		t.Errorf("expected ok to succeed, got %d", results[0].ExitCode)
+	}
+	if results[1].ExitCode == 0 {
+		t.Error("expected fail to fail")
+	}
+	if !strings.Contains(string(results[0].Log), "ok-output") {
+		t.Errorf("expected ok log to contain its output, got %q", results[0].Log)
+	}
+	if strings.Contains(string(results[0].Log), "fail-output") {
+		t.Error("logs should be kept per target")
+	}
+	if !strings.Contains(out.String(), "ok") || !strings.Contains(out.String(), "fail-output") {
+		t.Errorf("expected prefixed output, got %q", out.String())
+	}
+}
@@ -0,0 +1,56 @@
+//go:build !windows
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"unsafe"
+)
+
+var termSignal os.Signal = syscall.SIGTERM
+
+// setProcessGroup places the command in a new process group so that make and
+// every recipe it spawns can be signalled together.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// setForeground places the command in a new process group. When stdin is the
+// controlling terminal and mk is in the foreground, that group also becomes the terminal's foreground
+// group, so Ctrl-C reaches make directly and recipes can still read input.
+// The returned function hands the terminal back to mk once make has exited.
+func setForeground(cmd *exec.Cmd) (restore func()) {
+	fd := int(os.Stdin.Fd())
+	var pgrp int32
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), syscall.TIOCGPGRP, uintptr(unsafe.Pointer(&pgrp)))
+	if errno != 0 || int(pgrp) != syscall.Getpgrp() {
+		// Not a terminal, or mk itself runs in the background.
+		setProcessGroup(cmd)
+		return func() {}
+	}
+
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true, Foreground: true, Ctty: fd}
+	return func() {
+		// mk is a background process until it reclaims the terminal, which
+		// would otherwise stop it with SIGTTOU.
+		signal.Ignore(syscall.SIGTTOU)
+		defer signal.Reset(syscall.SIGTTOU)
+		self := int32(syscall.Getpgrp())
+		syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), syscall.TIOCSPGRP, uintptr(unsafe.Pointer(&self)))
+	}
+}
+
+func signalGroup(pid int, sig os.Signal) error {
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		s = syscall.SIGTERM
+	}
+	return syscall.Kill(-pid, s)
+}
+
+func killGroup(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGKILL)
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+)
+
+var termSignal os.Signal = os.Kill
+
+// setProcessGroup is a no-op on Windows: process groups cannot be signalled.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// setForeground is a no-op on Windows: the console delivers Ctrl-C to make itself.
+func setForeground(cmd *exec.Cmd) (restore func()) {
+	return func() {}
+}
+
+func signalGroup(pid int, sig os.Signal) error {
+	return killGroup(pid)
+}
+
+func killGroup(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
@@ -0,0 +1,93 @@
+package runner
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// Process is a make job started in the background.
+type Process struct {
+	job    Job
+	pid    int
+	start  time.Time
+	done   chan struct{}
+	result Result
+}
+
+// Start launches job in its own process group, writing its output to stdout
+// and stderr. The job does not read from the terminal.
+//
+// When mk's output is a terminal and the platform supports it, the job is
+// given a pseudo-terminal of its own so that it keeps emitting colors; its
+// combined output is then written to stdout.
+func Start(job Job, stdout, stderr io.Writer) (*Process, error) {
+	if p, ok := startDetached(job, stdout); ok {
+		return p, nil
+	}
+	cmd := job.Command()
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	setProcessGroup(cmd)
+	return startCmd(job, cmd, nil)
+}
+
+// startCmd starts a prepared command and collects its result in the
+// background. afterWait, if set, runs once the command has exited and
+// before Done is closed.
+func startCmd(job Job, cmd *exec.Cmd, afterWait func()) (*Process, error) {
+	p := &Process{job: job, start: time.Now(), done: make(chan struct{})}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	p.pid = cmd.Process.Pid
+
+	go func() {
+		err := cmd.Wait()
+		if afterWait != nil {
+			afterWait()
+		}
+		p.result = Result{
+			Job:       job,
+			StartedAt: p.start,
+			ExitCode:  ExitCode(err),
+			Duration:  time.Since(p.start),
+			Signal:    exitSignal(err),
+		}
+		close(p.done)
+	}()
+	return p, nil
+}
+
+// Done is closed once the process has exited.
+func (p *Process) Done() <-chan struct{} {
+	return p.done
+}
+
+// Result returns the outcome of the process. It is only valid after Done is closed.
+func (p *Process) Result() Result {
+	return p.result
+}
+
+// Signal sends sig to the whole process group of the job.
+func (p *Process) Signal(sig os.Signal) error {
+	return signalGroup(p.pid, sig)
+}
+
+// Stop asks the process group to terminate and kills it if it is still
+// running after grace. Stop returns once the process has exited.
+func (p *Process) Stop(grace time.Duration) {
+	select {
+	case <-p.done:
+		return
+	default:
+	}
+	_ = p.Signal(termSignal)
+	select {
+	case <-p.done:
+	case <-time.After(grace):
+		_ = killGroup(p.pid)
+		<-p.done
+	}
+}
@@ -0,0 +1,215 @@
+//go:build linux
+
+package runner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/subut0n/mk/internal/term"
+)
+
+// ptyDrainTimeout bounds how long output is still read once make has exited,
+// in case a background process keeps the terminal open.
+const ptyDrainTimeout = time.Second
+
+type winsize struct {
+	Row, Col, Xpixel, Ypixel uint16
+}
+
+// runPTY runs job on a new pseudo-terminal so that make and the tools it
+// runs keep emitting colors while the output is captured. mk's terminal is
+// put in raw mode and keystrokes are relayed to make, window size changes
+// are propagated, and everything make prints is copied to out. It reports
+// false, without running anything, when stdin and stdout are not both
+// terminals or no pseudo-terminal can be allocated.
+func runPTY(job Job, out io.Writer, grace time.Duration) (Result, bool) {
+	if !term.IsTerminal(os.Stdin) || !term.IsTerminal(os.Stdout) {
+		return Result{}, false
+	}
+	r := newRelay(grace)
+	p, master, err := startPTY(job, out, true)
+	if err != nil {
+		r.stop()
+		return Result{}, false
+	}
+	r.add(p)
+
+	// In raw mode every key, including Ctrl-C, is passed to the
+	// pseudo-terminal, whose own line discipline then handles echo and
+	// signals.
+	if state, err := term.MakeRaw(); err == nil {
+		defer term.Restore(state)
+	}
+
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	defer signal.Stop(winch)
+
+	stop := make(chan struct{})
+	go relayInput(master, stop)
+
+	for waiting := true; waiting; {
+		select {
+		case <-winch:
+			copyWinsize(master)
+		case <-p.Done():
+			waiting = false
+		}
+	}
+	close(stop)
+	sig := r.stop()
+
+	res := p.Result()
+	if res.Signal == nil {
+		res.Signal = sig
+	}
+	return res, true
+}
+
+// startDetached starts job on a pseudo-terminal of its own without input,
+// for jobs that run next to others or in the background, so that they keep
+// emitting colors. It reports false when mk's output is not a terminal or
+// the job could not be started that way.
+func startDetached(job Job, out io.Writer) (*Process, bool) {
+	if !term.IsTerminal(os.Stdout) {
+		return nil, false
+	}
+	// The master side is closed once the process has exited.
+	p, _, err := startPTY(job, out, false)
+	return p, err == nil
+}
+
+// startPTY starts job in a new session whose controlling terminal is the
+// slave side of a fresh pseudo-terminal. Output read from the master side is
+// copied to out until the process has exited. Without input, the job reads
+// from /dev/null instead of the pseudo-terminal.
+func startPTY(job Job, out io.Writer, input bool) (*Process, *os.File, error) {
+	master, slave, err := openPTY()
+	if err != nil {
+		return nil, nil, err
+	}
+	copyWinsize(master)
+
+	cmd := job.Command()
+	cmd.Stdout = slave
+	cmd.Stderr = slave
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true, Ctty: 1}
+	if input {
+		cmd.Stdin = slave
+		cmd.SysProcAttr.Ctty = 0
+	}
+
+	copied := make(chan struct{})
+	go func() {
+		// Reading fails with EIO once every slave descriptor is closed.
+		io.Copy(out, master)
+		close(copied)
+	}()
+
+	p, err := startCmd(job, cmd, func() {
+		select {
+		case <-copied:
+		case <-time.After(ptyDrainTimeout):
+		}
+		master.Close()
+	})
+	slave.Close()
+	if err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	return p, master, nil
+}
+
+// openPTY allocates a pseudo-terminal pair through /dev/ptmx.
+func openPTY() (master, slave *os.File, err error) {
+	master, err = os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var unlock int32
+	var n uint32
+	if err = masterIoctl(master, syscall.TIOCSPTLCK, unsafe.Pointer(&unlock)); err == nil {
+		err = masterIoctl(master, syscall.TIOCGPTN, unsafe.Pointer(&n))
+	}
+	if err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+
+	slave, err = os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	return master, slave, nil
+}
+
+// masterIoctl issues an ioctl on the master side without switching the file
+// to blocking mode (which File.Fd would do), so that closing it still
+// interrupts a pending read.
+func masterIoctl(master *os.File, req uintptr, arg unsafe.Pointer) error {
+	rc, err := master.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var errno syscall.Errno
+	if err := rc.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(arg))
+	}); err != nil {
+		return err
+	}
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// copyWinsize applies the size of mk's terminal to the pseudo-terminal.
+func copyWinsize(master *os.File) {
+	var ws winsize
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, os.Stdout.Fd(), syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws))); errno != 0 {
+		return
+	}
+	_ = masterIoctl(master, syscall.TIOCSWINSZ, unsafe.Pointer(&ws))
+}
+
+// relayInput copies keystrokes from stdin to the pseudo-terminal until stop
+// is closed. Stdin is polled so that no read is left pending afterwards,
+// which would swallow input meant for mk.
+func relayInput(master *os.File, stop <-chan struct{}) {
+	buf := make([]byte, 1024)
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		if !stdinReadable(100 * time.Millisecond) {
+			continue
+		}
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return
+		}
+		if _, err := master.Write(buf[:n]); err != nil {
+			return
+		}
+	}
+}
+
+// stdinReadable waits up to timeout for input on stdin (file descriptor 0).
+func stdinReadable(timeout time.Duration) bool {
+	var fds syscall.FdSet
+	fds.Bits[0] = 1
+	tv := syscall.NsecToTimeval(timeout.Nanoseconds())
+	n, err := syscall.Select(1, &fds, nil, nil, &tv)
+	return err == nil && n > 0
+}
@@ -0,0 +1,38 @@
+package runner
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStartPTYGivesMakeATerminal(t *testing.T) {
+	path := writeTempMakefile(t, `tty:
+	@test -t 1 && printf '\033[32mtty\033[0m\n' || echo notty
+	@test -t 0 || echo noinput
+	@exit 3
+`)
+	if master, slave, err := openPTY(); err != nil {
+		t.Skipf("pseudo-terminals not available: %v", err)
+	} else {
+		master.Close()
+		slave.Close()
+	}
+
+	var out bytes.Buffer
+	p, _, err := startPTY(Job{Makefile: path, Target: "tty"}, &out, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-p.Done()
+
+	if got := out.String(); !strings.Contains(got, "\033[32mtty\033[0m") {
+		t.Errorf("expected colored output from a terminal, got %q", got)
+	}
+	if got := out.String(); !strings.Contains(got, "noinput") {
+		t.Errorf("expected a job without input to read from elsewhere, got %q", got)
+	}
+	if code := p.Result().ExitCode; code != 2 {
+		t.Errorf("expected make's exit code 2, got %d", code)
+	}
+}
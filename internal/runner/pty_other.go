@@ -0,0 +1,20 @@
+//go:build !linux
+
+package runner
+
+import (
+	"io"
+	"time"
+)
+
+// runPTY is only implemented on Linux; elsewhere make always runs attached
+// to mk's own terminal.
+func runPTY(job Job, out io.Writer, grace time.Duration) (Result, bool) {
+	return Result{}, false
+}
+
+// startDetached is only implemented on Linux; elsewhere jobs started with
+// Start write to pipes.
+func startDetached(job Job, out io.Writer) (*Process, bool) {
+	return nil, false
+}
@@ -0,0 +1,76 @@
+package runner
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// Job describes a single make invocation.
+type Job struct {
+	Makefile string
+	Target   string
+	Args     []string // extra make arguments (e.g. VAR=value)
+}
+
+// Command builds the make command for the job.
+func (j Job) Command() *exec.Cmd {
+	args := append([]string{"-f", j.Makefile}, j.Args...)
+	args = append(args, j.Target)
+	return exec.Command("make", args...)
+}
+
+// DryRun returns the commands make would execute for the job (make -n),
+// without running them.
+func DryRun(job Job) (string, error) {
+	cmd := job.Command()
+	cmd.Args = append(cmd.Args[:1], append([]string{"-n"}, cmd.Args[1:]...)...)
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// Result reports the outcome of a job.
+type Result struct {
+	Job       Job
+	StartedAt time.Time
+	ExitCode  int
+	Duration  time.Duration
+	Signal    os.Signal // signal that interrupted the run, if any
+	Log       []byte    // full, unprefixed output of the job
+	Err       error     // non-nil when make could not be started
+}
+
+// Interrupted reports whether the run was stopped by a signal.
+func (r Result) Interrupted() bool {
+	return r.Signal != nil
+}
+
+// ExitCode converts the error returned by exec.Cmd.Wait into a process exit
+// status. Processes killed by a signal map to 128+signal, like shells do.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) {
+		return 1
+	}
+	if ws, ok := ee.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return 128 + int(ws.Signal())
+	}
+	return ee.ExitCode()
+}
+
+// exitSignal returns the signal that killed the process, if any.
+func exitSignal(err error) os.Signal {
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) {
+		return nil
+	}
+	if ws, ok := ee.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return ws.Signal()
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package runner
+
+import (
+	"io"
+	"os/exec"
+	"runtime"
+	"testing"
+)
+
+func TestExitCodeSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals are not supported on Windows")
+	}
+	err := exec.Command("sh", "-c", "kill -TERM $$").Run()
+	if code := ExitCode(err); code != 128+15 {
+		t.Errorf("expected 143 for SIGTERM, got %d", code)
+	}
+	if sig := exitSignal(err); sig == nil || SignalName(sig) != "SIGTERM" {
+		t.Errorf("expected SIGTERM, got %v", sig)
+	}
+}
+
+func TestRunPropagatesExitCode(t *testing.T) {
+	path := writeTempMakefile(t, `ok:
+	@true
+fail:
+	@exit 7
+`)
+
+	res := Run(Job{Makefile: path, Target: "ok"}, io.Discard, io.Discard, DefaultGrace)
+	if res.ExitCode != 0 || res.Interrupted() {
+		t.Errorf("expected success, got %d (signal %v)", res.ExitCode, res.Signal)
+	}
+
+	res = Run(Job{Makefile: path, Target: "fail"}, io.Discard, io.Discard, DefaultGrace)
+	if res.ExitCode != 2 {
+		t.Errorf("expected make's exit code 2, got %d", res.ExitCode)
+	}
+	if res.Interrupted() {
+		t.Error("a failed recipe is not an interruption")
+	}
+}
@@ -0,0 +1,103 @@
+package runner
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultGrace is how long an interrupted make process group gets to shut
+// down cleanly before it is killed.
+const DefaultGrace = 5 * time.Second
+
+// forwardedSignals are relayed from mk to the make process groups it runs.
+var forwardedSignals = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGHUP}
+
+// relay forwards termination signals received by mk to running processes.
+// Processes still running grace after the first signal are killed.
+type relay struct {
+	mu    sync.Mutex
+	procs []*Process
+	sig   os.Signal
+	ch    chan os.Signal
+	done  chan struct{}
+}
+
+func newRelay(grace time.Duration) *relay {
+	r := &relay{ch: make(chan os.Signal, 1), done: make(chan struct{})}
+	signal.Notify(r.ch, forwardedSignals...)
+
+	go func() {
+		var kill <-chan time.Time
+		for {
+			select {
+			case <-r.done:
+				return
+			case sig := <-r.ch:
+				r.mu.Lock()
+				if r.sig == nil {
+					r.sig = sig
+					kill = time.After(grace)
+				}
+				for _, p := range r.procs {
+					_ = p.Signal(sig)
+				}
+				r.mu.Unlock()
+			case <-kill:
+				r.mu.Lock()
+				for _, p := range r.procs {
+					select {
+					case <-p.Done():
+					default:
+						_ = killGroup(p.pid)
+					}
+				}
+				r.mu.Unlock()
+			}
+		}
+	}()
+	return r
+}
+
+// add registers a process; it immediately receives any signal already relayed.
+func (r *relay) add(p *Process) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.procs = append(r.procs, p)
+	if r.sig != nil {
+		_ = p.Signal(r.sig)
+	}
+}
+
+// received returns the first signal received so far, if any.
+func (r *relay) received() os.Signal {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sig
+}
+
+// stop ends forwarding and returns the first signal received, if any.
+func (r *relay) stop() os.Signal {
+	signal.Stop(r.ch)
+	close(r.done)
+	return r.received()
+}
+
+// SignalName returns the conventional name of a signal (e.g. SIGINT).
+func SignalName(sig os.Signal) string {
+	switch sig {
+	case os.Interrupt:
+		return "SIGINT"
+	case syscall.SIGTERM:
+		return "SIGTERM"
+	case syscall.SIGHUP:
+		return "SIGHUP"
+	case syscall.SIGKILL:
+		return "SIGKILL"
+	case syscall.SIGQUIT:
+		return "SIGQUIT"
+	}
+	return sig.String()
+}
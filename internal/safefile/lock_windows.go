@@ -0,0 +1,36 @@
+//go:build windows
+
+package safefile
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+const (
+	lockRetry = 10 * time.Millisecond
+	// A lock file older than lockStale was left behind by a process that
+	// died while holding it.
+	lockStale = 10 * time.Second
+)
+
+// lock creates the lock file exclusively, retrying while another process
+// holds it.
+func lock(name string) (func(), error) {
+	for {
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(name) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(name); err == nil && time.Since(info.ModTime()) > lockStale {
+			os.Remove(name)
+			continue
+		}
+		time.Sleep(lockRetry)
+	}
+}
@@ -0,0 +1,74 @@
+package safefile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CorruptError reports a file that could not be parsed and was moved aside
+// so that the next write does not destroy it.
+type CorruptError struct {
+	Path   string
+	Backup string // where the unreadable content was kept ("" if it could not be moved)
+	Err    error
+
+	// Dropped is the number of unreadable lines removed from a line-based
+	// file whose other lines were kept; 0 means the whole file was reset.
+	Dropped int
+}
+
+func (e *CorruptError) Error() string {
+	if e.Backup == "" {
+		return fmt.Sprintf("%s is corrupted: %v", e.Path, e.Err)
+	}
+	return fmt.Sprintf("%s is corrupted (%v), moved to %s", e.Path, e.Err, e.Backup)
+}
+
+func (e *CorruptError) Unwrap() error { return e.Err }
+
+// Lock takes an exclusive lock associated with path, waiting for other mk
+// processes to release it. The lock is held on a separate "<path>.lock" file
+// so that path itself can be replaced while locked.
+func Lock(path string) (unlock func(), err error) {
+	return lock(path + ".lock")
+}
+
+// WriteFile atomically replaces path with data: the content is written and
+// synced to a temporary file in the same directory, which is then renamed
+// over path. Readers see either the old or the new content, never a partial
+// write.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// MoveAside renames an unreadable file to "<path>.corrupt-<timestamp>" and
+// returns a CorruptError describing it.
+func MoveAside(path string, cause error) *CorruptError {
+	backup := path + ".corrupt-" + time.Now().Format("20060102-150405")
+	if err := os.Rename(path, backup); err != nil {
+		backup = ""
+	}
+	return &CorruptError{Path: path, Backup: backup, Err: cause}
+}
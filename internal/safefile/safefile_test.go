@@ -0,0 +1,87 @@
+package safefile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWriteFileReplaces(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	if err := WriteFile(path, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(path, []byte("new"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "new" {
+		t.Errorf("expected new content, got %q", data)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestLockSerializes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+	if err := os.WriteFile(path, []byte("0"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Read-modify-write cycles lose updates unless the lock serializes them.
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := Lock(path)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer unlock()
+			data, _ := os.ReadFile(path)
+			if err := WriteFile(path, append(data, '+'), 0600); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, _ := os.ReadFile(path)
+	if n := strings.Count(string(data), "+"); n != 20 {
+		t.Errorf("expected 20 updates, got %d", n)
+	}
+}
+
+func TestMoveAside(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	if err := os.WriteFile(path, []byte("{broken"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cause := errors.New("unexpected end of JSON input")
+	err := MoveAside(path, cause)
+	if err.Backup == "" || !strings.HasPrefix(err.Backup, path+".corrupt-") {
+		t.Fatalf("unexpected backup path %q", err.Backup)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("CorruptError should wrap its cause")
+	}
+	if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+		t.Error("corrupted file should have been moved")
+	}
+	if data, _ := os.ReadFile(err.Backup); string(data) != "{broken" {
+		t.Errorf("backup content lost: %q", data)
+	}
+}
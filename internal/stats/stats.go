@@ -0,0 +1,131 @@
+package stats
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/subut0n/mk/internal/history"
+)
+
+// TrendWeeks is the number of weeks covered by Target.WeeklyRuns.
+const TrendWeeks = 8
+
+// trendWindow is the number of recent runs whose median duration is compared
+// with the runs before them.
+const trendWindow = 10
+
+// Target aggregates the recorded runs of one target in one project.
+type Target struct {
+	Project     string     `json:"project"`
+	Target      string     `json:"target"`
+	Runs        int        `json:"runs"`
+	Succeeded   int        `json:"succeeded"`
+	Failed      int        `json:"failed"`
+	Interrupted int        `json:"interrupted"`
+	SuccessRate float64    `json:"success_rate"` // 0-1, over runs with a known outcome; -1 if none
+	MedianMs    int64      `json:"median_ms"`
+	P90Ms       int64      `json:"p90_ms"`
+	LastRun     time.Time  `json:"last_run"`
+	LastFailure *time.Time `json:"last_failure,omitempty"`
+
+	// WeeklyRuns counts the runs of each of the last TrendWeeks weeks,
+	// oldest first.
+	WeeklyRuns []int `json:"weekly_runs"`
+	// DurationChange compares the median duration of the last 10 runs with
+	// the 10 before them (0.25 means 25% slower). It is 0 when there are
+	// fewer than 20 timed runs.
+	DurationChange float64 `json:"duration_change"`
+}
+
+// Compute aggregates history entries (most recent first, as returned by
+// history.Manager) per project directory and target. Results are sorted by
+// project, then by number of runs, most used first.
+func Compute(entries []history.Entry, now time.Time) []Target {
+	type key struct{ project, target string }
+	groups := make(map[key][]history.Entry)
+	var order []key
+	for _, e := range entries {
+		k := key{e.Directory, e.Target}
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], e)
+	}
+
+	result := make([]Target, 0, len(order))
+	for _, k := range order {
+		result = append(result, aggregate(k.project, k.target, groups[k], now))
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Project != result[j].Project {
+			return result[i].Project < result[j].Project
+		}
+		return result[i].Runs > result[j].Runs
+	})
+	return result
+}
+
+// aggregate computes the statistics of one target from its runs, most
+// recent first.
+func aggregate(project, target string, runs []history.Entry, now time.Time) Target {
+	t := Target{
+		Project:     project,
+		Target:      target,
+		Runs:        len(runs),
+		SuccessRate: -1,
+		LastRun:     runs[0].ExecutedAt,
+		WeeklyRuns:  make([]int, TrendWeeks),
+	}
+
+	var durations []int64 // timed runs, most recent first
+	for _, e := range runs {
+		switch e.Status {
+		case history.StatusSuccess:
+			t.Succeeded++
+		case history.StatusFailed:
+			t.Failed++
+		case history.StatusInterrupted:
+			t.Interrupted++
+		}
+		if e.Status == history.StatusFailed && t.LastFailure == nil {
+			at := e.ExecutedAt
+			t.LastFailure = &at
+		}
+		if e.Status != history.StatusUnknown {
+			durations = append(durations, e.DurationMs)
+		}
+		if week := int(now.Sub(e.ExecutedAt) / (7 * 24 * time.Hour)); week >= 0 && week < TrendWeeks {
+			t.WeeklyRuns[TrendWeeks-1-week]++
+		}
+	}
+
+	if known := t.Succeeded + t.Failed + t.Interrupted; known > 0 {
+		t.SuccessRate = float64(t.Succeeded) / float64(known)
+	}
+	t.MedianMs = Percentile(durations, 50)
+	t.P90Ms = Percentile(durations, 90)
+	if len(durations) >= 2*trendWindow {
+		recent := Percentile(durations[:trendWindow], 50)
+		before := Percentile(durations[trendWindow:2*trendWindow], 50)
+		if before > 0 {
+			t.DurationChange = float64(recent-before) / float64(before)
+		}
+	}
+	return t
+}
+
+// Percentile returns the p-th percentile (nearest rank) of values, or 0 for
+// an empty slice. values is not modified.
+func Percentile(values []int64, p int) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
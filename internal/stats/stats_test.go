@@ -0,0 +1,88 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/subut0n/mk/internal/history"
+)
+
+func TestPercentile(t *testing.T) {
+	values := []int64{50, 10, 40, 20, 30, 60, 70, 80, 90, 100}
+	cases := map[int]int64{50: 50, 90: 90, 100: 100, 0: 10}
+	for p, want := range cases {
+		if got := Percentile(values, p); got != want {
+			t.Errorf("Percentile(%d) = %d, want %d", p, got, want)
+		}
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("empty input: got %d", got)
+	}
+	if values[0] != 50 {
+		t.Error("input must not be sorted in place")
+	}
+}
+
+func TestCompute(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	run := func(target, dir string, ago time.Duration, status history.Status, ms int64) history.Entry {
+		return history.Entry{Target: target, Directory: dir, ExecutedAt: now.Add(-ago), Status: status, DurationMs: ms}
+	}
+	day := 24 * time.Hour
+	entries := []history.Entry{ // most recent first
+		run("test", "/p", 1*time.Hour, history.StatusSuccess, 100),
+		run("test", "/p", 2*day, history.StatusFailed, 300),
+		run("build", "/p", 3*day, history.StatusSuccess, 1000),
+		run("test", "/p", 9*day, history.StatusFailed, 200),
+		run("test", "/p", 10*day, history.StatusInterrupted, 50),
+		run("deploy", "/a", 20*day, history.StatusUnknown, 0),
+	}
+
+	got := Compute(entries, now)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 groups, got %d", len(got))
+	}
+	if got[0].Project != "/a" || got[1].Target != "test" || got[2].Target != "build" {
+		t.Fatalf("unexpected order: %s/%s, %s/%s, %s/%s", got[0].Project, got[0].Target, got[1].Project, got[1].Target, got[2].Project, got[2].Target)
+	}
+
+	test := got[1]
+	if test.Runs != 4 || test.Succeeded != 1 || test.Failed != 2 || test.Interrupted != 1 {
+		t.Errorf("unexpected counts: %+v", test)
+	}
+	if test.SuccessRate != 0.25 {
+		t.Errorf("expected success rate 0.25, got %v", test.SuccessRate)
+	}
+	if test.MedianMs != 100 || test.P90Ms != 300 {
+		t.Errorf("expected median 100 and p90 300, got %d and %d", test.MedianMs, test.P90Ms)
+	}
+	if test.LastFailure == nil || !test.LastFailure.Equal(now.Add(-2*day)) {
+		t.Errorf("unexpected last failure %v", test.LastFailure)
+	}
+	want := []int{0, 0, 0, 0, 0, 0, 2, 2}
+	for i := range want {
+		if test.WeeklyRuns[i] != want[i] {
+			t.Errorf("expected weekly runs %v, got %v", want, test.WeeklyRuns)
+			break
+		}
+	}
+
+	if deploy := got[0]; deploy.SuccessRate != -1 || deploy.MedianMs != 0 {
+		t.Errorf("runs without outcome have no rate or duration: %+v", deploy)
+	}
+}
+
+func TestDurationChange(t *testing.T) {
+	now := time.Now()
+	var entries []history.Entry
+	for i := 0; i < 20; i++ {
+		ms := int64(100)
+		if i < 10 {
+			ms = 150 // the 10 most recent runs are 50% slower
+		}
+		entries = append(entries, history.Entry{Target: "build", Directory: "/p", ExecutedAt: now.Add(-time.Duration(i) * time.Hour), Status: history.StatusSuccess, DurationMs: ms})
+	}
+	if got := Compute(entries, now)[0].DurationChange; got != 0.5 {
+		t.Errorf("expected +50%%, got %v", got)
+	}
+}
@@ -0,0 +1,74 @@
+//go:build darwin
+
+package term
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+type termios struct {
+	Iflag  uint64
+	Oflag  uint64
+	Cflag  uint64
+	Lflag  uint64
+	Cc     [20]uint8
+	Ispeed uint64
+	Ospeed uint64
+}
+
+// State is the terminal configuration saved by MakeRaw.
+type State struct {
+	t termios
+}
+
+const (
+	ioctlGetTermios = syscall.TIOCGETA
+	ioctlSetTermios = syscall.TIOCSETA
+)
+
+// MakeRaw puts the terminal of stdin in raw mode, where every key is read
+// as soon as it is pressed, without echo or signals, and returns its
+// previous state for Restore.
+func MakeRaw() (*State, error) {
+	return MakeRawRead(1, 0)
+}
+
+// MakeRawRead is MakeRaw with the VMIN and VTIME settings of reads: a read
+// returns once vmin bytes are available, or after vtime tenths of a second
+// without input.
+func MakeRawRead(vmin, vtime uint8) (*State, error) {
+	fd := os.Stdin.Fd()
+	var t termios
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlGetTermios, uintptr(unsafe.Pointer(&t))); errno != 0 {
+		return nil, errno
+	}
+	old := &State{t}
+
+	t.Lflag &^= syscall.ECHO | syscall.ICANON | syscall.ISIG
+	t.Iflag &^= syscall.IXON | syscall.ICRNL
+	t.Cc[syscall.VMIN] = vmin
+	t.Cc[syscall.VTIME] = vtime
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlSetTermios, uintptr(unsafe.Pointer(&t))); errno != 0 {
+		return nil, errno
+	}
+	return old, nil
+}
+
+// Restore puts the terminal of stdin back in state.
+func Restore(state *State) {
+	if state == nil {
+		return
+	}
+	fd := os.Stdin.Fd()
+	syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlSetTermios, uintptr(unsafe.Pointer(&state.t)))
+}
+
+// IsTerminal reports whether f is connected to a terminal.
+func IsTerminal(f *os.File) bool {
+	var t termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), ioctlGetTermios, uintptr(unsafe.Pointer(&t)))
+	return errno == 0
+}
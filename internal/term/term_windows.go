@@ -0,0 +1,25 @@
+//go:build windows
+
+package term
+
+import (
+	"fmt"
+	"os"
+)
+
+type State struct{}
+
+func MakeRaw() (*State, error) {
+	return nil, fmt.Errorf("raw mode not supported on Windows")
+}
+
+func MakeRawRead(vmin, vtime uint8) (*State, error) {
+	return MakeRaw()
+}
+
+func Restore(state *State) {}
+
+// IsTerminal always reports false on Windows, where raw mode is not supported.
+func IsTerminal(f *os.File) bool {
+	return false
+}
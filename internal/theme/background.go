@@ -0,0 +1,55 @@
+package theme
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ParseXColor reads a color in the X11 form used by terminals to answer
+// color queries: "rgb:RRRR/GGGG/BBBB", with 1 to 4 hex digits per
+// component.
+func ParseXColor(spec string) (Color, error) {
+	parts := strings.Split(strings.TrimPrefix(spec, "rgb:"), "/")
+	if !strings.HasPrefix(spec, "rgb:") || len(parts) != 3 {
+		return Color{}, fmt.Errorf("invalid color %q", spec)
+	}
+	var c [3]uint8
+	for i, p := range parts {
+		v, err := strconv.ParseUint(p, 16, 16)
+		if err != nil || len(p) == 0 || len(p) > 4 {
+			return Color{}, fmt.Errorf("invalid color %q", spec)
+		}
+		full := uint64(1)<<(4*len(p)) - 1
+		c[i] = uint8(v * 255 / full)
+	}
+	return Color{kind: rgb, r: c[0], g: c[1], b: c[2]}, nil
+}
+
+// IsLight reports whether c is a light color, one on which the default
+// gray and bright colors are hard to read.
+func (c Color) IsLight() bool {
+	r, g, b := c.RGB()
+	return 299*int(r)+587*int(g)+114*int(b) > 1000*128
+}
+
+// BackgroundFromEnv reads the background color from COLORFGBG, set by
+// rxvt, Konsole and other terminals as "fg;bg" with ANSI color indices. Of
+// the 16 ANSI colors only white and bright white are light backgrounds; the
+// bright variants of red, blue and the others are dark. Grays of the
+// 256-color palette are light above the middle of the ramp. It reports false
+// when the variable is missing or does not tell.
+func BackgroundFromEnv() (light, ok bool) {
+	fields := strings.Split(os.Getenv("COLORFGBG"), ";")
+	bg, err := strconv.Atoi(fields[len(fields)-1])
+	switch {
+	case err != nil || bg < 0 || bg > 255:
+		return false, false
+	case bg < 16:
+		return bg == 7 || bg == 15, true
+	case bg >= 232:
+		return Color{kind: indexed, index: bg}.IsLight(), true
+	}
+	return false, false
+}
@@ -0,0 +1,175 @@
+package theme
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Depth is the number of colors a terminal can display.
+type Depth int
+
+const (
+	Depth16   Depth = iota // the 16 ANSI colors
+	Depth256               // the xterm 256-color palette
+	TrueColor              // 24-bit RGB
+)
+
+// DetectDepth guesses the color depth of the terminal from COLORTERM and
+// TERM. Terminals that do not announce more are assumed to have 16 colors.
+func DetectDepth() Depth {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	term := os.Getenv("TERM")
+	switch {
+	case strings.HasSuffix(term, "-direct"), os.Getenv("WT_SESSION") != "":
+		return TrueColor
+	case strings.Contains(term, "256color"):
+		return Depth256
+	}
+	return Depth16
+}
+
+type kind int
+
+const (
+	basic   kind = iota // index into the 16 ANSI colors
+	indexed             // index into the 256-color palette
+	rgb                 // 24-bit color
+)
+
+// Color is a parsed color specification.
+type Color struct {
+	kind    kind
+	index   int
+	r, g, b uint8
+}
+
+// names are the 16 ANSI colors, in the order of their codes.
+var names = []string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"bright-black", "bright-red", "bright-green", "bright-yellow",
+	"bright-blue", "bright-magenta", "bright-cyan", "bright-white",
+}
+
+var aliases = map[string]string{
+	"gray":   "bright-black",
+	"grey":   "bright-black",
+	"purple": "magenta",
+}
+
+// basicRGB approximates the 16 ANSI colors with the xterm defaults, to find
+// the closest one to another color.
+var basicRGB = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the component values of the 6×6×6 cube of the 256-color
+// palette.
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// Parse reads a color given as one of the 16 ANSI color names ("red",
+// "bright-blue", "gray"...), an index of the 256-color palette ("208") or
+// an RGB color ("#ff8700").
+func Parse(spec string) (Color, error) {
+	s := strings.ToLower(strings.TrimSpace(spec))
+	if alias, ok := aliases[s]; ok {
+		s = alias
+	}
+	for i, name := range names {
+		if s == name {
+			return Color{kind: basic, index: i}, nil
+		}
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 || n > 255 {
+			return Color{}, fmt.Errorf("color index %d out of range 0-255", n)
+		}
+		return Color{kind: indexed, index: n}, nil
+	}
+	if hex, ok := strings.CutPrefix(s, "#"); ok && len(hex) == 6 {
+		if v, err := strconv.ParseUint(hex, 16, 32); err == nil {
+			return Color{kind: rgb, r: uint8(v >> 16), g: uint8(v >> 8), b: uint8(v)}, nil
+		}
+	}
+	return Color{}, fmt.Errorf("invalid color %q (expected a color name, 0-255 or #rrggbb)", spec)
+}
+
+// Sequence returns the escape sequence setting c as foreground color,
+// downgraded to the closest color the depth can display.
+func (c Color) Sequence(depth Depth) string {
+	switch {
+	case c.kind == rgb && depth == TrueColor:
+		return fmt.Sprintf("\033[38;2;%d;%d;%dm", c.r, c.g, c.b)
+	case c.kind == rgb && depth == Depth256:
+		return fmt.Sprintf("\033[38;5;%dm", nearest256(c.r, c.g, c.b))
+	case c.kind == indexed && depth >= Depth256:
+		return fmt.Sprintf("\033[38;5;%dm", c.index)
+	}
+	i := c.index
+	if c.kind != basic {
+		i = nearestBasic(c.RGB())
+	}
+	if i < 8 {
+		return fmt.Sprintf("\033[%dm", 30+i)
+	}
+	return fmt.Sprintf("\033[%dm", 90+i-8)
+}
+
+// RGB returns the components of c. The 16 ANSI colors vary between
+// terminals and are approximated with the xterm defaults.
+func (c Color) RGB() (r, g, b uint8) {
+	switch c.kind {
+	case rgb:
+		return c.r, c.g, c.b
+	case basic:
+		v := basicRGB[c.index]
+		return v[0], v[1], v[2]
+	}
+	switch i := c.index; {
+	case i < 16:
+		v := basicRGB[i]
+		return v[0], v[1], v[2]
+	case i < 232:
+		i -= 16
+		return uint8(cubeLevels[i/36]), uint8(cubeLevels[i/6%6]), uint8(cubeLevels[i%6])
+	default:
+		v := uint8(8 + 10*(i-232))
+		return v, v, v
+	}
+}
+
+// nearest256 returns the index of the 256-color palette closest to an RGB
+// color, among the color cube and the gray ramp.
+func nearest256(r, g, b uint8) int {
+	best, bestDist := 0, -1
+	for i := 16; i < 256; i++ {
+		cr, cg, cb := Color{kind: indexed, index: i}.RGB()
+		if d := distance(r, g, b, cr, cg, cb); bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// nearestBasic returns the ANSI color closest to an RGB color.
+func nearestBasic(r, g, b uint8) int {
+	best, bestDist := 0, -1
+	for i, v := range basicRGB {
+		if d := distance(r, g, b, v[0], v[1], v[2]); bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+func distance(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr, dg, db := int(r1)-int(r2), int(g1)-int(g2), int(b1)-int(b2)
+	return dr*dr + dg*dg + db*db
+}
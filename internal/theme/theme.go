@@ -0,0 +1,192 @@
+// Package theme defines the colors of the mk interface: named roles for the
+// interface itself and a palette cycled over target names. Colors are given
+// as ANSI color names, 256-color indices or #rrggbb, and are downgraded to
+// what the terminal can display.
+package theme
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/subut0n/mk/internal/ansi"
+)
+
+// Theme is a set of colors. A role left empty keeps the color of the base
+// theme. Light holds the roles that differ on a light background.
+type Theme struct {
+	Base        string   `json:"base,omitempty"`        // built-in theme the others start from (default rainbow)
+	Title       string   `json:"title,omitempty"`       // titles and menu numbers
+	Cursor      string   `json:"cursor,omitempty"`      // menu cursor
+	Description string   `json:"description,omitempty"` // target descriptions
+	Success     string   `json:"success,omitempty"`     // successful runs and confirmations
+	Error       string   `json:"error,omitempty"`       // failures and errors
+	Muted       string   `json:"muted,omitempty"`       // hints and other secondary text
+	Palette     []string `json:"palette,omitempty"`     // target names, cycled
+	Light       *Theme   `json:"light,omitempty"`       // variant for light backgrounds
+}
+
+// defaultRoles are the interface colors of the built-in themes. On a light
+// background, gray text is darkened.
+var defaultRoles = Theme{
+	Title:       "magenta",
+	Cursor:      "magenta",
+	Description: "gray",
+	Success:     "green",
+	Error:       "red",
+	Muted:       "gray",
+}
+
+var defaultLightRoles = Theme{
+	Description: "240",
+	Muted:       "240",
+}
+
+// builtins are the themes mk ships with, selected by color_scheme. The
+// light palettes replace the yellows and bright colors, which are hard to
+// read on a light background.
+var builtins = []struct {
+	name           string
+	palette, light []string
+}{
+	{"rainbow",
+		[]string{"red", "yellow", "green", "cyan", "blue", "magenta"},
+		[]string{"red", "136", "green", "30", "blue", "magenta"}},
+	{"deuteranopia",
+		[]string{"blue", "yellow", "cyan", "magenta", "bright-blue", "bright-yellow"},
+		[]string{"blue", "136", "30", "magenta", "25", "130"}},
+	{"tritanopia",
+		[]string{"red", "magenta", "green", "bright-red", "bright-magenta", "bright-green"},
+		[]string{"red", "magenta", "green", "124", "127", "28"}},
+	{"high-contrast",
+		[]string{"bright-red", "bright-yellow", "bright-green", "bright-cyan", "bright-blue", "bright-magenta"},
+		[]string{"160", "130", "28", "30", "20", "90"}},
+}
+
+// Builtin returns the built-in theme called name.
+func Builtin(name string) (Theme, bool) {
+	for _, b := range builtins {
+		if b.name == name {
+			t, light := defaultRoles, defaultLightRoles
+			t.Palette, light.Palette = b.palette, b.light
+			t.Light = &light
+			return t, true
+		}
+	}
+	return Theme{}, false
+}
+
+// Builtins returns the names of the built-in themes.
+func Builtins() []string {
+	names := make([]string, len(builtins))
+	for i, b := range builtins {
+		names[i] = b.name
+	}
+	return names
+}
+
+// Resolve returns the colors of t for a light or a dark background: the
+// roles of its base, then those t sets, each overridden by the light
+// variant on a light background. The result has no Light variant.
+func (t Theme) Resolve(light bool) Theme {
+	base, ok := Builtin(t.Base)
+	if !ok {
+		base, _ = Builtin("rainbow")
+	}
+	layers := []*Theme{&base, base.Light, &t, t.Light}
+	if !light {
+		layers = []*Theme{&base, &t}
+	}
+	var r Theme
+	for _, l := range layers {
+		if l != nil {
+			r.overlay(*l)
+		}
+	}
+	r.Base = t.Base
+	return r
+}
+
+// overlay sets the roles of t to those o sets.
+func (t *Theme) overlay(o Theme) {
+	for _, r := range []struct{ role, from *string }{
+		{&t.Title, &o.Title},
+		{&t.Cursor, &o.Cursor},
+		{&t.Description, &o.Description},
+		{&t.Success, &o.Success},
+		{&t.Error, &o.Error},
+		{&t.Muted, &o.Muted},
+	} {
+		if *r.from != "" {
+			*r.role = *r.from
+		}
+	}
+	if len(o.Palette) > 0 {
+		t.Palette = o.Palette
+	}
+}
+
+// Validate reports the colors of t that cannot be parsed and an unknown
+// base.
+func (t Theme) Validate() error {
+	var errs []error
+	if _, ok := Builtin(t.Base); t.Base != "" && !ok {
+		errs = append(errs, fmt.Errorf("unknown base theme %q", t.Base))
+	}
+	for _, spec := range append([]string{t.Title, t.Cursor, t.Description, t.Success, t.Error, t.Muted}, t.Palette...) {
+		if spec == "" {
+			continue
+		}
+		if _, err := Parse(spec); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if t.Light != nil {
+		light := *t.Light
+		light.Base = ""
+		if err := light.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("light: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Apply makes the roles of the resolved theme t the interface colors, as
+// sequences for the given depth. An invalid color keeps the default of its
+// role.
+func (t Theme) Apply(depth Depth) {
+	fallback, _ := Builtin("rainbow")
+	seq := func(spec, def string) string {
+		c, err := Parse(spec)
+		if err != nil {
+			c, _ = Parse(def)
+		}
+		return c.Sequence(depth)
+	}
+	ansi.SetRoles(ansi.Roles{
+		Title:       seq(t.Title, fallback.Title),
+		Cursor:      seq(t.Cursor, fallback.Cursor),
+		Description: seq(t.Description, fallback.Description),
+		Success:     seq(t.Success, fallback.Success),
+		Error:       seq(t.Error, fallback.Error),
+		Muted:       seq(t.Muted, fallback.Muted),
+	})
+}
+
+// PaletteCodes returns the sequences of the palette of the resolved theme t
+// for the given depth, leaving out invalid colors, or a single empty code
+// while colors are disabled.
+func (t Theme) PaletteCodes(depth Depth) []string {
+	if !ansi.ColorsEnabled() {
+		return []string{""}
+	}
+	var codes []string
+	for _, spec := range t.Palette {
+		if c, err := Parse(spec); err == nil {
+			codes = append(codes, c.Sequence(depth))
+		}
+	}
+	if len(codes) == 0 {
+		return []string{""}
+	}
+	return codes
+}
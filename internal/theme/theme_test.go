@@ -0,0 +1,181 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/subut0n/mk/internal/ansi"
+)
+
+func TestParseAndDowngrade(t *testing.T) {
+	cases := []struct {
+		spec                  string
+		true24, c256, basic16 string
+	}{
+		{"red", "\033[31m", "\033[31m", "\033[31m"},
+		{"Bright-Blue", "\033[94m", "\033[94m", "\033[94m"},
+		{"gray", "\033[90m", "\033[90m", "\033[90m"},
+		{"208", "\033[38;5;208m", "\033[38;5;208m", "\033[33m"},
+		{"#ff8700", "\033[38;2;255;135;0m", "\033[38;5;208m", "\033[33m"},
+		{"#808080", "\033[38;2;128;128;128m", "\033[38;5;244m", "\033[90m"},
+	}
+	for _, c := range cases {
+		color, err := Parse(c.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", c.spec, err)
+		}
+		for _, d := range []struct {
+			depth Depth
+			want  string
+		}{{TrueColor, c.true24}, {Depth256, c.c256}, {Depth16, c.basic16}} {
+			if got := color.Sequence(d.depth); got != d.want {
+				t.Errorf("%q at depth %d: got %q, want %q", c.spec, d.depth, got, d.want)
+			}
+		}
+	}
+
+	for _, spec := range []string{"", "orange", "256", "-1", "#fff", "#gg0000"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q): expected an error", spec)
+		}
+	}
+}
+
+func TestDetectDepth(t *testing.T) {
+	cases := []struct {
+		colorterm, term string
+		want            Depth
+	}{
+		{"truecolor", "xterm-256color", TrueColor},
+		{"", "xterm-256color", Depth256},
+		{"", "xterm-direct", TrueColor},
+		{"", "xterm", Depth16},
+		{"", "", Depth16},
+	}
+	t.Setenv("WT_SESSION", "")
+	for _, c := range cases {
+		t.Setenv("COLORTERM", c.colorterm)
+		t.Setenv("TERM", c.term)
+		if got := DetectDepth(); got != c.want {
+			t.Errorf("COLORTERM=%q TERM=%q: got %d, want %d", c.colorterm, c.term, got, c.want)
+		}
+	}
+}
+
+func TestResolveAndValidate(t *testing.T) {
+	custom := Theme{Base: "tritanopia", Title: "#5f87ff", Muted: "245"}
+	if err := custom.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	r := custom.Resolve(false)
+	tritanopia, _ := Builtin("tritanopia")
+	if r.Title != "#5f87ff" || r.Muted != "245" || r.Cursor != tritanopia.Cursor || len(r.Palette) != len(tritanopia.Palette) {
+		t.Errorf("unexpected resolved theme %+v", r)
+	}
+
+	bad := Theme{Base: "sepia", Error: "crimson", Palette: []string{"red", "999"}}
+	if err := bad.Validate(); err == nil {
+		t.Error("expected errors for the unknown base and invalid colors")
+	}
+}
+
+func TestApply(t *testing.T) {
+	defer ansi.SetRoles(ansi.Roles{
+		Title: "\033[35m", Cursor: "\033[35m", Description: "\033[90m",
+		Success: "\033[32m", Error: "\033[31m", Muted: "\033[90m",
+	})
+
+	Theme{Description: "#808080", Error: "not-a-color"}.Apply(Depth256)
+	if ansi.Description != "\033[38;5;244m" {
+		t.Errorf("description role not applied: %q", ansi.Description)
+	}
+	if ansi.Red != "\033[31m" || ansi.Purple != "\033[35m" {
+		t.Errorf("roles left empty or invalid should keep their default: %q %q", ansi.Red, ansi.Purple)
+	}
+
+	if codes := (Theme{Palette: []string{"bogus"}}).PaletteCodes(Depth16); len(codes) != 1 || codes[0] != "" {
+		t.Errorf("a palette without valid colors should give a single empty code, got %q", codes)
+	}
+}
+
+func TestLightVariant(t *testing.T) {
+	custom := Theme{Description: "250", Light: &Theme{Description: "238"}}
+	if r := custom.Resolve(false); r.Description != "250" || r.Light != nil {
+		t.Errorf("unexpected dark variant %+v", r)
+	}
+	if r := custom.Resolve(true); r.Description != "238" || r.Muted != "240" || r.Palette[1] != "136" {
+		t.Errorf("unexpected light variant %+v", r)
+	}
+	// A role set for both backgrounds wins over the light variant of the base.
+	if r := (Theme{Muted: "245"}).Resolve(true); r.Muted != "245" {
+		t.Errorf("expected the theme's own muted color, got %q", r.Muted)
+	}
+	if err := (Theme{Light: &Theme{Title: "nope"}}).Validate(); err == nil {
+		t.Error("expected an error for an invalid light color")
+	}
+}
+
+func TestBackground(t *testing.T) {
+	cases := []struct {
+		spec  string
+		light bool
+	}{
+		{"rgb:ffff/ffff/ffff", true},
+		{"rgb:fdfd/f6f6/e3e3", true},
+		{"rgb:0000/0000/0000", false},
+		{"rgb:28/2c/34", false},
+		{"rgb:e/e/e", true},
+	}
+	for _, c := range cases {
+		color, err := ParseXColor(c.spec)
+		if err != nil {
+			t.Fatalf("ParseXColor(%q): %v", c.spec, err)
+		}
+		if color.IsLight() != c.light {
+			t.Errorf("%q: expected light=%v", c.spec, c.light)
+		}
+	}
+	for _, spec := range []string{"", "#ffffff", "rgb:ff/ff", "rgb:fffff/0/0", "rgb:zz/00/00"} {
+		if _, err := ParseXColor(spec); err == nil {
+			t.Errorf("ParseXColor(%q): expected an error", spec)
+		}
+	}
+
+	for value, want := range map[string][2]bool{
+		"15;0": {false, true}, "0;15": {true, true}, "0;default;7": {true, true},
+		"15;9": {false, true}, "15;12": {false, true}, "0;14": {false, true},
+		"0;250": {true, true}, "15;236": {false, true}, "15;100": {false, false},
+		"15;default": {false, false}, "": {false, false},
+	} {
+		t.Setenv("COLORFGBG", value)
+		if light, ok := BackgroundFromEnv(); light != want[0] || ok != want[1] {
+			t.Errorf("COLORFGBG=%q: got %v %v, want %v", value, light, ok, want)
+		}
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	red, _ := Parse("#ff0000")
+	green, _ := Parse("#00ff00")
+	distance := func(a, b Color) int {
+		ar, ag, ab := a.RGB()
+		br, bg, bb := b.RGB()
+		return distance(ar, ag, ab, br, bg, bb)
+	}
+
+	// Red and green are told apart easily with normal vision, much less
+	// without green or red cones.
+	normal := distance(red, green)
+	for _, v := range []Vision{Deuteranopia, Protanopia} {
+		if d := distance(red.Simulate(v), green.Simulate(v)); d*3 > normal {
+			t.Errorf("vision %d: red and green should look alike, distance %d of %d", v, d, normal)
+		}
+	}
+	if d := distance(red.Simulate(Tritanopia), green.Simulate(Tritanopia)); d*2 < normal {
+		t.Errorf("tritanopia should keep red and green apart, distance %d of %d", d, normal)
+	}
+
+	white, _ := Parse("#ffffff")
+	if r, g, b := white.Simulate(Deuteranopia).RGB(); r < 250 || g < 250 || b < 250 {
+		t.Errorf("white should stay white, got %d,%d,%d", r, g, b)
+	}
+}
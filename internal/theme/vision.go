@@ -0,0 +1,76 @@
+package theme
+
+import "math"
+
+// Vision is a color vision deficiency, simulated to preview how a palette
+// looks to the people who have it.
+type Vision int
+
+const (
+	Deuteranopia Vision = iota // no green cones
+	Protanopia                 // no red cones
+	Tritanopia                 // no blue cones
+)
+
+// visionMatrices are the simulation matrices of Machado, Oliveira and
+// Fernandes (2009) at full severity, applied to linear RGB.
+var visionMatrices = map[Vision][3][3]float64{
+	Deuteranopia: {
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	},
+	Protanopia: {
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	},
+	Tritanopia: {
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	},
+}
+
+// Simulate returns the color c appears as with the vision deficiency v.
+func (c Color) Simulate(v Vision) Color {
+	r, g, b := c.RGB()
+	in := [3]float64{linear(r), linear(g), linear(b)}
+	var out [3]uint8
+	for i, row := range visionMatrices[v] {
+		out[i] = encode(row[0]*in[0] + row[1]*in[1] + row[2]*in[2])
+	}
+	return Color{kind: rgb, r: out[0], g: out[1], b: out[2]}
+}
+
+// linear converts an sRGB component to linear light.
+func linear(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// encode converts linear light to an sRGB component.
+func encode(c float64) uint8 {
+	c = min(max(c, 0), 1)
+	if c <= 0.0031308 {
+		c *= 12.92
+	} else {
+		c = 1.055*math.Pow(c, 1/2.4) - 0.055
+	}
+	return uint8(math.Round(c * 255))
+}
+
+// SimulatedCodes returns the sequences of the palette of the resolved theme
+// t as it appears with the vision deficiency v, for the given depth.
+func (t Theme) SimulatedCodes(v Vision, depth Depth) []string {
+	var codes []string
+	for _, spec := range t.Palette {
+		if c, err := Parse(spec); err == nil {
+			codes = append(codes, c.Simulate(v).Sequence(depth))
+		}
+	}
+	return codes
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"bytes"
+	"os"
+	"time"
+
+	"github.com/subut0n/mk/internal/ansi"
+	"github.com/subut0n/mk/internal/term"
+)
+
+// QueryBackground asks the terminal for its background color with an OSC 11
+// query and returns the color of the answer, such as "rgb:ffff/ffff/ffff".
+// It reports false when stdin and stdout are not both terminals, or when the
+// terminal does not answer within timeout.
+//
+// The query is followed by a device attributes request (DA1), which
+// terminals answer after the OSC 11 one, if any: a DA1 answer alone means the
+// terminal does not support the query. Input is read until the DA1 answer
+// arrives or timeout has passed, checked every tenth of a second. An answer
+// arriving after timeout is not consumed and reaches the next reader of the
+// terminal.
+func QueryBackground(timeout time.Duration) (string, bool) {
+	if ansi.Dumb() || !term.IsTerminal(os.Stdin) || !term.IsTerminal(os.Stdout) {
+		return "", false
+	}
+	state, err := term.MakeRawRead(0, 1)
+	if err != nil {
+		return "", false
+	}
+	defer term.Restore(state)
+
+	if _, err := os.Stdout.WriteString("\033]11;?\033\\\033[c"); err != nil {
+		return "", false
+	}
+
+	var answer []byte
+	buf := make([]byte, 64)
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		n, _ := os.Stdin.Read(buf)
+		answer = append(answer, buf[:n]...)
+		if i := bytes.Index(answer, []byte("\033[?")); i >= 0 && bytes.IndexByte(answer[i:], 'c') >= 0 {
+			break
+		}
+	}
+	return parseOSC11(answer)
+}
+
+// parseOSC11 extracts the color of an OSC 11 answer, terminated by BEL or
+// ST, from the input read after the query.
+func parseOSC11(answer []byte) (string, bool) {
+	_, rest, ok := bytes.Cut(answer, []byte("\033]11;"))
+	if !ok {
+		return "", false
+	}
+	end := bytes.IndexAny(rest, "\a\033")
+	if end < 0 {
+		return "", false
+	}
+	return string(rest[:end]), true
+}
@@ -4,16 +4,18 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"github.com/subut0n/mk/internal/term"
 )
 
 // CaptureKey enters raw mode and captures a single printable key press.
 // Non-printable keys, '/' (reserved for filter), Enter, and Escape are rejected.
 func CaptureKey() (byte, error) {
-	oldState, err := makeRaw()
+	oldState, err := term.MakeRaw()
 	if err != nil {
 		return 0, err
 	}
-	defer restoreTerminal(oldState)
+	defer term.Restore(oldState)
 
 	for {
 		b := make([]byte, 4)
@@ -0,0 +1,273 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/subut0n/mk/internal/ansi"
+	"github.com/subut0n/mk/internal/config"
+	"github.com/subut0n/mk/internal/i18n"
+)
+
+// Item is one row of an interactive list.
+type Item struct {
+	Icon   string // optional symbol shown before the label (may contain ANSI codes)
+	Label  string
+	Detail string // secondary text shown in gray
+	Match  string // extra text matched by the filter besides the label
+}
+
+// ListOptions configures RunList. The embedded Options provide the key scheme
+// and colors of the target menu.
+type ListOptions struct {
+	Options
+	Title   string
+	Actions string // help text for Enter and, when Delete is set, the delete key
+
+	// Delete removes the item at index (in the original slice) from its
+	// underlying store. When set, Del or x removes the highlighted item.
+	Delete func(index int) error
+}
+
+// RunList displays items in an interactive list that can be navigated and
+// filtered like the target menu. It returns the index of the item chosen with
+// Enter, or false if the user quit or the terminal does not support raw mode.
+func RunList(items []Item, opts ListOptions) (int, bool) {
+	if len(items) == 0 {
+		return 0, false
+	}
+	if opts.KeyScheme == "" {
+		opts.KeyScheme = config.KeySchemeArrows
+	}
+
+	restore, err := enterRawMode()
+	if err != nil {
+		return 0, false
+	}
+	defer restore()
+
+	// remaining holds the indices of the items that have not been deleted.
+	remaining := make([]int, len(items))
+	for i := range items {
+		remaining[i] = i
+	}
+
+	cursor := 0
+	scroll := 0
+	maxVisible := 15
+	filter := ""
+	filtering := false
+	prevLines := 0
+	status := ""
+
+	for {
+		visible := filterItems(items, remaining, filter)
+		if cursor >= len(visible) {
+			cursor = max(len(visible)-1, 0)
+		}
+		if scroll > cursor {
+			scroll = cursor
+		}
+
+		prevLines = renderList(items, visible, cursor, scroll, maxVisible, filter, filtering, status, prevLines, opts)
+		status = ""
+
+		b := make([]byte, 4)
+		n, err := os.Stdin.Read(b)
+		if err != nil {
+			clearLines(prevLines)
+			return 0, false
+		}
+		if n == 0 {
+			continue
+		}
+		key := b[:n]
+
+		if filtering {
+			switch {
+			case key[0] == 27:
+				filtering = false
+				filter = ""
+			case key[0] == 13:
+				filtering = false
+			case key[0] == 127 || key[0] == 8:
+				if len(filter) > 0 {
+					filter = filter[:len(filter)-1]
+				}
+			case key[0] >= 32 && key[0] < 127:
+				filter += string(key[0])
+			}
+			cursor = 0
+			scroll = 0
+			continue
+		}
+
+		switch {
+		case isQuitKey(key[0], opts.Options):
+			clearLines(prevLines)
+			return 0, false
+
+		case key[0] == '/':
+			filtering = true
+			filter = ""
+
+		case key[0] == 13:
+			if len(visible) == 0 {
+				continue
+			}
+			clearLines(prevLines)
+			return visible[cursor], true
+
+		case opts.Delete != nil && isDeleteKey(key, opts.Options):
+			if len(visible) == 0 {
+				continue
+			}
+			idx := visible[cursor]
+			if err := opts.Delete(idx); err != nil {
+				status = fmt.Sprintf(i18n.Get().ErrDeleteEntry, err)
+				continue
+			}
+			for i, r := range remaining {
+				if r == idx {
+					remaining = append(remaining[:i], remaining[i+1:]...)
+					break
+				}
+			}
+			if len(remaining) == 0 {
+				clearLines(prevLines)
+				return 0, false
+			}
+
+		case n >= 3 && key[0] == 27 && key[1] == 91:
+			switch key[2] {
+			case 65: // arrow up
+				moveUp(&cursor, &scroll)
+			case 66: // arrow down
+				moveDown(&cursor, &scroll, maxVisible, len(visible))
+			}
+
+		case isUpKey(key[0], opts.Options):
+			moveUp(&cursor, &scroll)
+
+		case isDownKey(key[0], opts.Options):
+			moveDown(&cursor, &scroll, maxVisible, len(visible))
+		}
+	}
+}
+
+// isDeleteKey reports whether key is the Delete key (ESC [ 3 ~) or x, unless
+// x is bound to custom navigation.
+func isDeleteKey(key []byte, opts Options) bool {
+	if len(key) == 4 && key[0] == 27 && key[1] == 91 && key[2] == '3' && key[3] == '~' {
+		return true
+	}
+	if len(key) != 1 {
+		return false
+	}
+	if opts.KeyScheme == config.KeySchemeCustom &&
+		(eqCaseInsensitive(opts.CustomUpKey, 'x') || eqCaseInsensitive(opts.CustomDownKey, 'x')) {
+		return false
+	}
+	return key[0] == 'x' || key[0] == 'X'
+}
+
+// filterItems returns the indices of the remaining items whose label or
+// match text contains filter (case-insensitive).
+func filterItems(items []Item, remaining []int, filter string) []int {
+	if filter == "" {
+		return remaining
+	}
+	f := strings.ToLower(filter)
+	var result []int
+	for _, i := range remaining {
+		if strings.Contains(strings.ToLower(items[i].Label), f) ||
+			strings.Contains(strings.ToLower(items[i].Match), f) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// listHelpLine describes the navigation keys of the current scheme followed
+// by the list's own actions.
+func listHelpLine(opts ListOptions) string {
+	keys, quitHint := navigationHelp(opts.Options)
+	return fmt.Sprintf("%s  %s%s", ansi.Gray, fmt.Sprintf(i18n.Get().ListHelpFmt, keys, opts.Actions, quitHint), ansi.Reset)
+}
+
+// navigationHelp returns the navigation keys of the current scheme and the
+// quit key, for help lines.
+func navigationHelp(opts Options) (keys, quitHint string) {
+	keys = "↑/↓"
+	quitHint = "q quit"
+	switch opts.KeyScheme {
+	case config.KeySchemeWASD:
+		keys = "↑/↓/w/s"
+	case config.KeySchemeCustom:
+		keys = fmt.Sprintf("↑/↓/%s/%s", strings.ToLower(KeyDisplayName(opts.CustomUpKey)), strings.ToLower(KeyDisplayName(opts.CustomDownKey)))
+		if opts.CustomUpKey == 'q' || opts.CustomDownKey == 'q' {
+			quitHint = "Ctrl+C quit"
+		}
+	}
+	return keys, quitHint
+}
+
+func renderList(items []Item, visible []int, cursor, scroll, maxVisible int, filter string, filtering bool, status string, prevLines int, opts ListOptions) int {
+	clearLines(prevLines)
+
+	lines := 0
+	printLine := func(s string) {
+		fmt.Println(s)
+		lines++
+	}
+
+	msg := i18n.Get()
+	printLine(fmt.Sprintf("%s%s%s%s", ansi.Bold, ansi.Purple, opts.Title, ansi.Reset))
+
+	if filtering {
+		printLine(fmt.Sprintf("%s  %s%s%s█%s", ansi.Gray, msg.FilterLabel, ansi.Reset, filter, ansi.Reset))
+	} else if filter != "" {
+		printLine(fmt.Sprintf("%s  %s%s%s%s", ansi.Gray, msg.FilterActiveLabel, ansi.Reset, filter, ansi.Reset))
+	} else {
+		printLine(listHelpLine(opts))
+	}
+	printLine("")
+
+	if len(visible) == 0 {
+		printLine(fmt.Sprintf("%s  %s%s", ansi.Gray, msg.NoMatchingEntries, ansi.Reset))
+	}
+	end := min(scroll+maxVisible, len(visible))
+	for i := scroll; i < end; i++ {
+		it := items[visible[i]]
+		c := ""
+		if len(opts.ColorPalette) > 0 {
+			c = opts.ColorPalette[i%len(opts.ColorPalette)]
+		}
+		marker := "  "
+		label := fmt.Sprintf("%s%-30s%s", c, it.Label, ansi.Reset)
+		if i == cursor {
+			marker = ansi.Bold + ansi.Cursor + "▶ " + ansi.Reset
+			label = ansi.Bold + label
+		}
+		line := "  " + marker
+		if it.Icon != "" {
+			line += it.Icon + " "
+		}
+		line += label
+		if it.Detail != "" {
+			line += fmt.Sprintf("  %s%s%s", ansi.Description, it.Detail, ansi.Reset)
+		}
+		printLine(line)
+	}
+	if len(visible) > maxVisible {
+		printLine(fmt.Sprintf("%s  "+msg.ListCount+"%s", ansi.Gray, cursor+1, len(visible), ansi.Reset))
+	}
+
+	if status != "" {
+		printLine("")
+		printLine(fmt.Sprintf("  %s%s%s", ansi.Red, status, ansi.Reset))
+	}
+
+	return lines
+}
@@ -2,9 +2,11 @@ package ui
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"sort"
 	"strings"
 	"syscall"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/subut0n/mk/internal/config"
 	"github.com/subut0n/mk/internal/i18n"
 	"github.com/subut0n/mk/internal/parser"
+	"github.com/subut0n/mk/internal/term"
 )
 
 // Options configures the interactive menu behavior.
@@ -20,11 +23,36 @@ type Options struct {
 	ColorPalette  []string // ANSI color codes for target names (cycled)
 	CustomUpKey   byte     // custom up navigation key (only used when KeyScheme == "custom")
 	CustomDownKey byte     // custom down navigation key (only used when KeyScheme == "custom")
+
+	// Preview returns the commands a target would run (make -n). When set,
+	// Tab shows them below the menu and Enter confirms the real run.
+	Preview func(target string) (string, error)
+
+	// Order selects how Frecency, the targets' scores from the project's
+	// history, reorders the menu. InitialTarget (typically the last target
+	// run) is highlighted when the menu opens.
+	Order         config.MenuOrder
+	Frecency      map[string]float64
+	InitialTarget string
+}
+
+// recentGroupSize is the number of targets pinned under "Recently used".
+const recentGroupSize = 3
+
+// maxPreviewLines bounds the preview area so the menu stays on screen.
+const maxPreviewLines = 12
+
+// preview holds the dry-run output shown for a target.
+type preview struct {
+	target parser.Target
+	lines  []string
+	err    error
 }
 
 // SelectionResult holds the user's target selection.
+// Targets are listed in the order they should be executed.
 type SelectionResult struct {
-	Target    *parser.Target
+	Targets   []parser.Target
 	Confirmed bool
 }
 
@@ -38,37 +66,30 @@ func Run(targets []parser.Target, opts Options) SelectionResult {
 		opts.KeyScheme = config.KeySchemeArrows
 	}
 
-	oldState, err := makeRaw()
+	targets, pinned := orderTargets(targets, opts)
+
+	restore, err := enterRawMode()
 	if err != nil {
 		return runFallbackMenu(targets, opts.ColorPalette)
 	}
-	defer restoreTerminal(oldState)
-
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
-	done := make(chan struct{})
-	defer close(done)
-	defer signal.Stop(sigCh)
-	go func() {
-		select {
-		case <-sigCh:
-			restoreTerminal(oldState)
-			fmt.Print(ansi.ShowCursor)
-			os.Exit(130)
-		case <-done:
-		}
-	}()
-
-	fmt.Print(ansi.HideCursor)
-	defer fmt.Print(ansi.ShowCursor)
+	defer restore()
 
 	cursor := 0
 	scroll := 0
 	maxVisible := 15
+	for i, t := range targets {
+		if t.Name == opts.InitialTarget {
+			cursor = i
+			scroll = max(cursor-maxVisible+1, 0)
+			break
+		}
+	}
 	filter := ""
 	filtering := false
 	filtered := targets
 	prevLines := 0
+	var selected []parser.Target
+	var pv *preview
 
 	for {
 		filtered = applyFilter(targets, filter)
@@ -80,7 +101,11 @@ func Run(targets []parser.Target, opts Options) SelectionResult {
 			}
 		}
 
-		prevLines = renderMenu(filtered, cursor, scroll, maxVisible, filter, filtering, prevLines, opts)
+		groups := pinned
+		if filter != "" {
+			groups = 0
+		}
+		prevLines = renderMenu(filtered, selected, cursor, scroll, maxVisible, groups, filter, filtering, pv, prevLines, opts)
 
 		b := make([]byte, 4)
 		n, err := os.Stdin.Read(b)
@@ -112,22 +137,70 @@ func Run(targets []parser.Target, opts Options) SelectionResult {
 			continue
 		}
 
+		// While a preview is shown, Enter confirms the real run and Escape or
+		// Tab cancels it; any other key closes it and is handled normally.
+		// With a selection, Enter adds the previewed target to it and runs
+		// the selection as the menu's own Enter does.
+		if pv != nil {
+			switch {
+			case key[0] == 13 && len(selected) == 0:
+				clearLines(prevLines)
+				return SelectionResult{Targets: []parser.Target{pv.target}, Confirmed: true}
+			case key[0] == 13 && selectionIndex(selected, pv.target.Name) == 0:
+				selected = append(selected, pv.target)
+			case key[0] == 9 || (n == 1 && key[0] == 27):
+				pv = nil
+				continue
+			}
+			pv = nil
+		}
+
 		switch {
 		case isQuitKey(key[0], opts):
 			clearLines(prevLines)
 			return SelectionResult{}
 
+		case key[0] == 9 && opts.Preview != nil:
+			if len(filtered) == 0 {
+				continue
+			}
+			t := filtered[cursor]
+			out, err := opts.Preview(t.Name)
+			pv = &preview{target: t, lines: splitPreview(out), err: err}
+
 		case key[0] == '/':
 			filtering = true
 			filter = ""
 
-		case key[0] == 13:
+		case key[0] == ' ':
 			if len(filtered) == 0 {
 				continue
 			}
-			selected := filtered[cursor]
+			selected = toggleSelected(selected, filtered[cursor])
+
+		case key[0] == 13:
+			if len(selected) == 0 {
+				if len(filtered) == 0 {
+					continue
+				}
+				clearLines(prevLines)
+				return SelectionResult{Targets: []parser.Target{filtered[cursor]}, Confirmed: true}
+			}
+			if len(selected) == 1 {
+				clearLines(prevLines)
+				return SelectionResult{Targets: selected, Confirmed: true}
+			}
 			clearLines(prevLines)
-			return SelectionResult{Target: &selected, Confirmed: true}
+			prevLines = 0
+			ordered, ok, quit := runOrderEditor(selected, opts)
+			if quit {
+				return SelectionResult{}
+			}
+			if ok {
+				return SelectionResult{Targets: ordered, Confirmed: true}
+			}
+			// Back to the menu: keep the (possibly reordered) selection
+			selected = ordered
 
 		case n >= 3 && key[0] == 27 && key[1] == 91:
 			switch key[2] {
@@ -146,6 +219,168 @@ func Run(targets []parser.Target, opts Options) SelectionResult {
 	}
 }
 
+// orderTargets applies opts.Order to the Makefile's targets. With
+// MenuOrderRecent, the most used targets are moved to the top and their count
+// is returned so that the menu can label the two groups.
+func orderTargets(targets []parser.Target, opts Options) (ordered []parser.Target, pinned int) {
+	if len(opts.Frecency) == 0 {
+		return targets, 0
+	}
+	ranked := append([]parser.Target(nil), targets...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return opts.Frecency[ranked[i].Name] > opts.Frecency[ranked[j].Name]
+	})
+
+	switch opts.Order {
+	case config.MenuOrderFrecency:
+		return ranked, 0
+	case config.MenuOrderRecent:
+		for pinned < len(ranked) && pinned < recentGroupSize && opts.Frecency[ranked[pinned].Name] > 0 {
+			pinned++
+		}
+		if pinned == 0 || pinned == len(targets) {
+			return targets, 0
+		}
+		ordered = append(ordered, ranked[:pinned]...)
+		for _, t := range targets {
+			if !containsTarget(ranked[:pinned], t.Name) {
+				ordered = append(ordered, t)
+			}
+		}
+		return ordered, pinned
+	}
+	return targets, 0
+}
+
+func containsTarget(targets []parser.Target, name string) bool {
+	return selectionIndex(targets, name) > 0
+}
+
+// errDumbTerminal reports a terminal that cannot draw menus (TERM=dumb).
+var errDumbTerminal = errors.New("terminal does not support cursor movement")
+
+// enterRawMode switches the terminal to raw mode and hides the cursor; the
+// returned function undoes both. Until then, an interrupt or termination
+// signal restores the terminal and exits with status 130.
+func enterRawMode() (restore func(), err error) {
+	if ansi.Dumb() {
+		return nil, errDumbTerminal
+	}
+	oldState, err := term.MakeRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-sigCh:
+			term.Restore(oldState)
+			fmt.Print(ansi.ShowCursor)
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	fmt.Print(ansi.HideCursor)
+	return func() {
+		fmt.Print(ansi.ShowCursor)
+		signal.Stop(sigCh)
+		close(done)
+		term.Restore(oldState)
+	}, nil
+}
+
+// runOrderEditor lets the user review and reorder the selected targets before
+// launch. Space grabs the item under the cursor so that navigation moves it;
+// Enter confirms, Escape returns to the menu and q/Ctrl+C quits.
+func runOrderEditor(selected []parser.Target, opts Options) (ordered []parser.Target, confirmed, quit bool) {
+	ordered = append([]parser.Target(nil), selected...)
+	cursor := 0
+	grabbed := false
+	prevLines := 0
+
+	move := func(delta int) {
+		next := cursor + delta
+		if next < 0 || next >= len(ordered) {
+			return
+		}
+		if grabbed {
+			ordered[cursor], ordered[next] = ordered[next], ordered[cursor]
+		}
+		cursor = next
+	}
+
+	for {
+		prevLines = renderOrder(ordered, cursor, grabbed, prevLines, opts)
+
+		b := make([]byte, 4)
+		n, err := os.Stdin.Read(b)
+		if err != nil {
+			clearLines(prevLines)
+			return ordered, false, true
+		}
+		if n == 0 {
+			continue
+		}
+		key := b[:n]
+
+		switch {
+		case isQuitKey(key[0], opts):
+			clearLines(prevLines)
+			return ordered, false, true
+
+		case n == 1 && key[0] == 27:
+			clearLines(prevLines)
+			return ordered, false, false
+
+		case key[0] == 13:
+			clearLines(prevLines)
+			return ordered, true, false
+
+		case key[0] == ' ':
+			grabbed = !grabbed
+
+		case n >= 3 && key[0] == 27 && key[1] == 91:
+			switch key[2] {
+			case 65: // arrow up
+				move(-1)
+			case 66: // arrow down
+				move(1)
+			}
+
+		case isUpKey(key[0], opts):
+			move(-1)
+
+		case isDownKey(key[0], opts):
+			move(1)
+		}
+	}
+}
+
+// toggleSelected adds t to the selection, or removes it if already selected.
+// Selection order is preserved so that it can be used as the run order.
+func toggleSelected(selected []parser.Target, t parser.Target) []parser.Target {
+	for i, s := range selected {
+		if s.Name == t.Name {
+			return append(selected[:i:i], selected[i+1:]...)
+		}
+	}
+	return append(selected, t)
+}
+
+// selectionIndex returns the 1-based position of name in the selection, or 0.
+func selectionIndex(selected []parser.Target, name string) int {
+	for i, s := range selected {
+		if s.Name == name {
+			return i + 1
+		}
+	}
+	return 0
+}
+
 func moveUp(cursor, scroll *int) {
 	if *cursor > 0 {
 		*cursor--
@@ -228,7 +463,10 @@ func helpLine(opts Options) string {
 	}
 }
 
-func renderMenu(targets []parser.Target, cursor, scroll, maxVisible int, filter string, filtering bool, prevLines int, opts Options) int {
+// renderMenu draws the target menu. When pinned is non-zero, the first pinned
+// targets are shown under a "Recently used" heading, the others under "All
+// targets".
+func renderMenu(targets, selected []parser.Target, cursor, scroll, maxVisible, pinned int, filter string, filtering bool, pv *preview, prevLines int, opts Options) int {
 	// Clear previous render
 	for i := 0; i < prevLines; i++ {
 		fmt.Print(ansi.Up + ansi.ClearLine)
@@ -251,6 +489,9 @@ func renderMenu(targets []parser.Target, cursor, scroll, maxVisible int, filter
 	} else {
 		printLine(helpLine(opts))
 	}
+	if len(selected) > 0 {
+		printLine(fmt.Sprintf("%s  %s%s", ansi.Green, fmt.Sprintf(msg.SelectedCount, len(selected)), ansi.Reset))
+	}
 	printLine("")
 
 	if len(targets) == 0 {
@@ -262,21 +503,32 @@ func renderMenu(targets []parser.Target, cursor, scroll, maxVisible int, filter
 		}
 		for i := scroll; i < end; i++ {
 			t := targets[i]
+			switch {
+			case pinned > 0 && i == 0:
+				printLine(fmt.Sprintf("  %s%s%s", ansi.Gray, msg.RecentGroup, ansi.Reset))
+			case pinned > 0 && i == pinned:
+				printLine("")
+				printLine(fmt.Sprintf("  %s%s%s", ansi.Gray, msg.AllTargetsGroup, ansi.Reset))
+			}
+			check := " "
+			if selectionIndex(selected, t.Name) > 0 {
+				check = ansi.Green + "✓" + ansi.Reset
+			}
 			var line string
 			if i == cursor {
 				c := ansi.Purple
 				if len(opts.ColorPalette) > 0 {
 					c = opts.ColorPalette[i%len(opts.ColorPalette)]
 				}
-				line = fmt.Sprintf("  %s%s▶ %s%s%-28s%s", ansi.Bold, ansi.Purple, ansi.Bold, c, t.Name, ansi.Reset)
+				line = fmt.Sprintf("  %s%s▶%s%s %s%s%-28s%s", ansi.Bold, ansi.Cursor, ansi.Reset, check, ansi.Bold, c, t.Name, ansi.Reset)
 			} else if len(opts.ColorPalette) > 0 {
 				c := opts.ColorPalette[i%len(opts.ColorPalette)]
-				line = fmt.Sprintf("    %s%-28s%s", c, t.Name, ansi.Reset)
+				line = fmt.Sprintf("   %s %s%-28s%s", check, c, t.Name, ansi.Reset)
 			} else {
-				line = fmt.Sprintf("    %-28s", t.Name)
+				line = fmt.Sprintf("   %s %-28s", check, t.Name)
 			}
 			if t.Description != "" {
-				line += fmt.Sprintf("  %s%s%s", ansi.Gray, t.Description, ansi.Reset)
+				line += fmt.Sprintf("  %s%s%s", ansi.Description, t.Description, ansi.Reset)
 			}
 			printLine(line)
 		}
@@ -285,6 +537,77 @@ func renderMenu(targets []parser.Target, cursor, scroll, maxVisible int, filter
 		}
 	}
 
+	if pv != nil {
+		printLine("")
+		printLine(fmt.Sprintf("  %s%s%s%s", ansi.Bold, ansi.Purple, fmt.Sprintf(msg.PreviewTitle, pv.target.Name), ansi.Reset))
+		switch {
+		case pv.err != nil:
+			printLine(fmt.Sprintf("  %s%s%s", ansi.Red, fmt.Sprintf(msg.PreviewError, pv.err), ansi.Reset))
+		case len(pv.lines) == 0:
+			printLine(fmt.Sprintf("  %s│%s %s", ansi.Gray, ansi.Reset, msg.PreviewEmpty))
+		}
+		for i, l := range pv.lines {
+			if i == maxPreviewLines {
+				printLine(fmt.Sprintf("  %s│ "+msg.PreviewMore+"%s", ansi.Gray, len(pv.lines)-maxPreviewLines, ansi.Reset))
+				break
+			}
+			printLine(fmt.Sprintf("  %s│%s %s", ansi.Gray, ansi.Reset, l))
+		}
+		printLine(fmt.Sprintf("%s  %s%s", ansi.Gray, msg.PreviewHelp, ansi.Reset))
+	}
+
+	return lines
+}
+
+// splitPreview splits dry-run output into display lines. Long lines are
+// truncated so that each one occupies a single terminal row.
+func splitPreview(out string) []string {
+	const maxWidth = 100
+	out = strings.TrimRight(out, "\n")
+	if out == "" {
+		return nil
+	}
+	lines := strings.Split(out, "\n")
+	for i, l := range lines {
+		l = strings.ReplaceAll(l, "\t", "    ")
+		if r := []rune(l); len(r) > maxWidth {
+			l = string(r[:maxWidth-1]) + "…"
+		}
+		lines[i] = l
+	}
+	return lines
+}
+
+// renderOrder draws the run-order editor shown when several targets are selected.
+func renderOrder(ordered []parser.Target, cursor int, grabbed bool, prevLines int, opts Options) int {
+	clearLines(prevLines)
+
+	lines := 0
+	printLine := func(s string) {
+		fmt.Println(s)
+		lines++
+	}
+
+	msg := i18n.Get()
+	printLine(fmt.Sprintf("%s%s%s%s", ansi.Bold, ansi.Purple, msg.OrderTitle, ansi.Reset))
+	printLine(fmt.Sprintf("%s  %s%s", ansi.Gray, msg.OrderHelp, ansi.Reset))
+	printLine("")
+
+	for i, t := range ordered {
+		c := ""
+		if len(opts.ColorPalette) > 0 {
+			c = opts.ColorPalette[i%len(opts.ColorPalette)]
+		}
+		marker := "  "
+		if i == cursor {
+			marker = ansi.Bold + ansi.Cursor + "▶ " + ansi.Reset
+			if grabbed {
+				marker = ansi.Bold + ansi.Cursor + "⇅ " + ansi.Reset
+			}
+		}
+		printLine(fmt.Sprintf("  %s%s%2d.%s %s%s%s", marker, ansi.Gray, i+1, ansi.Reset, c, t.Name, ansi.Reset))
+	}
+
 	return lines
 }
 
@@ -324,7 +647,7 @@ func runFallbackMenu(targets []parser.Target, palette []string) SelectionResult
 			nameReset = ansi.Reset
 		}
 		if t.Description != "" {
-			fmt.Printf("  %s%2d.%s %s%-30s%s %s%s%s\n", numColor, i+1, ansi.Reset, nameColor, t.Name, nameReset, ansi.Gray, t.Description, ansi.Reset)
+			fmt.Printf("  %s%2d.%s %s%-30s%s %s%s%s\n", numColor, i+1, ansi.Reset, nameColor, t.Name, nameReset, ansi.Description, t.Description, ansi.Reset)
 		} else {
 			fmt.Printf("  %s%2d.%s %s%s%s\n", numColor, i+1, ansi.Reset, nameColor, t.Name, nameReset)
 		}
@@ -332,17 +655,37 @@ func runFallbackMenu(targets []parser.Target, palette []string) SelectionResult
 	fmt.Printf("\n%s%s%s", ansi.Gray, m.FallbackPrompt, ansi.Reset)
 
 	for {
-		input, _ := reader.ReadString('\n')
+		input, err := reader.ReadString('\n')
 		input = strings.TrimSpace(input)
 		if input == "q" {
 			return SelectionResult{}
 		}
+		if picked, ok := parseFallbackChoice(input, targets); ok {
+			return SelectionResult{Targets: picked, Confirmed: true}
+		}
+		if err != nil {
+			// End of input, as in CI: there is nobody left to ask.
+			fmt.Println()
+			return SelectionResult{}
+		}
+		fmt.Printf("%s"+m.FallbackInvalid+"%s", ansi.Red, len(targets), ansi.Reset)
+	}
+}
+
+// parseFallbackChoice parses one or more target numbers separated by spaces or
+// commas (e.g. "3 1 2") and returns the targets in the given order.
+func parseFallbackChoice(input string, targets []parser.Target) ([]parser.Target, bool) {
+	fields := strings.FieldsFunc(input, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(fields) == 0 {
+		return nil, false
+	}
+	var picked []parser.Target
+	for _, f := range fields {
 		var idx int
-		if _, err := fmt.Sscanf(input, "%d", &idx); err == nil && idx >= 1 && idx <= len(targets) {
-			t := targets[idx-1]
-			return SelectionResult{Target: &t, Confirmed: true}
-		} else {
-			fmt.Printf("%s"+m.FallbackInvalid+"%s", ansi.Red, len(targets), ansi.Reset)
+		if _, err := fmt.Sscanf(f, "%d", &idx); err != nil || idx < 1 || idx > len(targets) {
+			return nil, false
 		}
+		picked = append(picked, targets[idx-1])
 	}
+	return picked, true
 }
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/subut0n/mk/internal/config"
+	"github.com/subut0n/mk/internal/parser"
+)
+
+func TestOrderTargets(t *testing.T) {
+	targets := func(names ...string) []parser.Target {
+		var ts []parser.Target
+		for _, name := range names {
+			ts = append(ts, parser.Target{Name: name})
+		}
+		return ts
+	}
+	makefile := targets("build", "test", "lint", "deploy", "clean")
+	scores := map[string]float64{"deploy": 5, "lint": 4, "clean": 3, "test": 2}
+
+	cases := []struct {
+		name     string
+		order    config.MenuOrder
+		targets  []parser.Target
+		frecency map[string]float64
+		want     []string
+		pinned   int
+	}{
+		{"makefile", config.MenuOrderMakefile, makefile, scores, []string{"build", "test", "lint", "deploy", "clean"}, 0},
+		{"frecency", config.MenuOrderFrecency, makefile, scores, []string{"deploy", "lint", "clean", "test", "build"}, 0},
+		{"recent capped", config.MenuOrderRecent, makefile, scores, []string{"deploy", "lint", "clean", "build", "test"}, recentGroupSize},
+		{"recent few runs", config.MenuOrderRecent, makefile, map[string]float64{"clean": 1, "test": 2}, []string{"test", "clean", "build", "lint", "deploy"}, 2},
+		{"recent all pinned", config.MenuOrderRecent, targets("build", "test"), map[string]float64{"build": 1, "test": 2}, []string{"build", "test"}, 0},
+		{"no history", config.MenuOrderRecent, makefile, nil, []string{"build", "test", "lint", "deploy", "clean"}, 0},
+	}
+	for _, c := range cases {
+		ordered, pinned := orderTargets(c.targets, Options{Order: c.order, Frecency: c.frecency})
+		var got []string
+		for _, t := range ordered {
+			got = append(got, t.Name)
+		}
+		if !reflect.DeepEqual(got, c.want) || pinned != c.pinned {
+			t.Errorf("%s: got %v with %d pinned, want %v with %d", c.name, got, pinned, c.want, c.pinned)
+		}
+	}
+}
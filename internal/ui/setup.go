@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/subut0n/mk/internal/ansi"
+	"github.com/subut0n/mk/internal/config"
+	"github.com/subut0n/mk/internal/i18n"
+)
+
+// SelectStep asks a question of the setup in a menu navigated like the
+// target menu. Enter, → or the number of a choice picks it; ←, Backspace
+// or Escape goes back when the step allows it. It returns
+// config.ErrSelectUnavailable when the terminal does not support raw mode,
+// so that the setup can fall back to numbered questions.
+func SelectStep(step config.SetupStep, opts Options) (int, error) {
+	if len(step.Choices) == 0 {
+		return 0, config.ErrSelectUnavailable
+	}
+	restore, err := enterRawMode()
+	if err != nil {
+		return 0, config.ErrSelectUnavailable
+	}
+	defer restore()
+
+	cursor := min(max(step.Initial, 0), len(step.Choices)-1)
+	scroll := 0
+	prevLines := 0
+	for {
+		prevLines = renderStep(step, cursor, prevLines, opts)
+
+		b := make([]byte, 4)
+		n, err := os.Stdin.Read(b)
+		if err != nil {
+			clearLines(prevLines)
+			return 0, config.ErrSetupCancelled
+		}
+		if n == 0 {
+			continue
+		}
+		key := b[:n]
+
+		switch {
+		case n >= 3 && key[0] == 27 && key[1] == 91:
+			switch key[2] {
+			case 65: // arrow up
+				moveUp(&cursor, &scroll)
+			case 66: // arrow down
+				moveDown(&cursor, &scroll, len(step.Choices), len(step.Choices))
+			case 67: // arrow right
+				clearLines(prevLines)
+				return cursor, nil
+			case 68: // arrow left
+				if step.Back {
+					clearLines(prevLines)
+					return 0, config.ErrSetupBack
+				}
+			}
+
+		case n == 1 && (key[0] == 27 || key[0] == 127 || key[0] == 8):
+			if step.Back {
+				clearLines(prevLines)
+				return 0, config.ErrSetupBack
+			}
+
+		case key[0] == 13:
+			clearLines(prevLines)
+			return cursor, nil
+
+		case isQuitKey(key[0], opts):
+			clearLines(prevLines)
+			return 0, config.ErrSetupCancelled
+
+		case isUpKey(key[0], opts):
+			moveUp(&cursor, &scroll)
+
+		case isDownKey(key[0], opts):
+			moveDown(&cursor, &scroll, len(step.Choices), len(step.Choices))
+
+		case key[0] >= '1' && int(key[0]-'0') <= len(step.Choices):
+			clearLines(prevLines)
+			return int(key[0] - '1'), nil
+		}
+	}
+}
+
+// renderStep draws a setup step: its position, title and help, the header
+// lines and the choices.
+func renderStep(step config.SetupStep, cursor, prevLines int, opts Options) int {
+	clearLines(prevLines)
+
+	lines := 0
+	printLine := func(s string) {
+		fmt.Println(s)
+		lines++
+	}
+
+	m := i18n.Get()
+	if step.Steps > 1 {
+		printLine(fmt.Sprintf("%s  %s%s", ansi.Gray, fmt.Sprintf(m.SetupStepFmt, step.Step, step.Steps), ansi.Reset))
+	}
+	printLine(fmt.Sprintf("  %s%s%s", ansi.Bold, step.Title, ansi.Reset))
+	keys, quitHint := navigationHelp(opts)
+	help := fmt.Sprintf(m.SetupHelpFmt, keys, quitHint)
+	if step.Back {
+		help = fmt.Sprintf(m.SetupHelpBackFmt, keys, quitHint)
+	}
+	printLine(fmt.Sprintf("%s  %s%s", ansi.Gray, help, ansi.Reset))
+	printLine("")
+
+	for _, l := range step.Header {
+		printLine(l)
+	}
+	if len(step.Header) > 0 {
+		printLine("")
+	}
+
+	for i, c := range step.Choices {
+		color := ""
+		if len(opts.ColorPalette) > 0 {
+			color = opts.ColorPalette[i%len(opts.ColorPalette)]
+		}
+		marker := "  "
+		label := fmt.Sprintf("%s%s%s", color, c.Label, ansi.Reset)
+		if i == cursor {
+			marker = ansi.Bold + ansi.Cursor + "▶ " + ansi.Reset
+			label = ansi.Bold + label
+		}
+		line := fmt.Sprintf("  %s%s%d.%s %s", marker, ansi.Purple, i+1, ansi.Reset, label)
+		if c.Hint != "" {
+			line += fmt.Sprintf(" %s%s%s", ansi.Gray, c.Hint, ansi.Reset)
+		}
+		printLine(line)
+	}
+	return lines
+}
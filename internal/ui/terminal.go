@@ -0,0 +1,12 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/subut0n/mk/internal/term"
+)
+
+// IsTerminal reports whether f is connected to a terminal.
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(f)
+}
@@ -0,0 +1,218 @@
+package watch
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultIgnore lists the directories skipped when no ignore rules are configured.
+var DefaultIgnore = []string{".git", ".hg", ".svn", "node_modules", "dist", "build", "bin"}
+
+const (
+	DefaultInterval = 500 * time.Millisecond
+	DefaultDebounce = 300 * time.Millisecond
+)
+
+// Options configures which files are polled for changes.
+type Options struct {
+	Root     string
+	Paths    []string      // files or directories to watch, relative to Root
+	Patterns []string      // glob patterns relative to Root; "**" matches any number of directories
+	Ignore   []string      // names or glob patterns excluded from the scan
+	Interval time.Duration // delay between two scans
+	Debounce time.Duration // quiet period required before a batch of changes is reported
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Snapshot maps watched file paths to their last known state.
+type Snapshot map[string]fileState
+
+// Scan records the state of every watched file. When neither Paths nor
+// Patterns are set, every file under Root is watched.
+func Scan(opts Options) Snapshot {
+	root := opts.Root
+	if root == "" {
+		root = "."
+	}
+	snap := Snapshot{}
+
+	add := func(p string, info fs.FileInfo) {
+		snap[filepath.ToSlash(p)] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	walk := func(dir string, keep func(rel string) bool) {
+		_ = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			rel, _ := filepath.Rel(root, p)
+			rel = filepath.ToSlash(rel)
+			if rel != "." && ignored(rel, opts.Ignore) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() || !keep(rel) {
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				add(rel, info)
+			}
+			return nil
+		})
+	}
+
+	all := func(string) bool { return true }
+
+	if len(opts.Paths) == 0 && len(opts.Patterns) == 0 {
+		walk(root, all)
+		return snap
+	}
+
+	for _, p := range opts.Paths {
+		full := filepath.Join(root, p)
+		info, err := os.Stat(full)
+		if err != nil {
+			continue
+		}
+		if info.IsDir() {
+			walk(full, all)
+		} else {
+			add(filepath.Clean(p), info)
+		}
+	}
+
+	if len(opts.Patterns) > 0 {
+		walk(root, func(rel string) bool {
+			for _, pat := range opts.Patterns {
+				if Match(pat, rel) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+
+	return snap
+}
+
+// Changed returns the sorted paths added, removed or modified between two snapshots.
+func Changed(old, cur Snapshot) []string {
+	var changed []string
+	for p, s := range cur {
+		if o, ok := old[p]; !ok || !o.modTime.Equal(s.modTime) || o.size != s.size {
+			changed = append(changed, p)
+		}
+	}
+	for p := range old {
+		if _, ok := cur[p]; !ok {
+			changed = append(changed, p)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// Watch polls the watched files until stop is closed. Bursts of changes are
+// coalesced: a batch is sent once no new change has been seen for Debounce.
+func Watch(opts Options, stop <-chan struct{}) <-chan []string {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultDebounce
+	}
+
+	out := make(chan []string)
+	go func() {
+		defer close(out)
+		prev := Scan(opts)
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+
+		pending := map[string]bool{}
+		var lastChange time.Time
+
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				cur := Scan(opts)
+				for _, p := range Changed(prev, cur) {
+					pending[p] = true
+					lastChange = now
+				}
+				prev = cur
+
+				if len(pending) == 0 || now.Sub(lastChange) < opts.Debounce {
+					continue
+				}
+				batch := make([]string, 0, len(pending))
+				for p := range pending {
+					batch = append(batch, p)
+				}
+				sort.Strings(batch)
+				pending = map[string]bool{}
+
+				select {
+				case out <- batch:
+				case <-stop:
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// Match reports whether the slash-separated path name matches pattern.
+// Patterns without a slash are matched against the base name only, so "*.go"
+// matches Go files in any directory. "**" matches zero or more directories.
+func Match(pattern, name string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(name))
+		return ok
+	}
+	return matchParts(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchParts(pat, parts []string) bool {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchParts(pat[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pat[0], parts[0]); !ok {
+			return false
+		}
+		pat, parts = pat[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+func ignored(rel string, rules []string) bool {
+	for _, r := range rules {
+		if Match(r, rel) {
+			return true
+		}
+	}
+	return false
+}
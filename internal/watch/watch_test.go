@@ -0,0 +1,109 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "internal/ui/menu.go", true},
+		{"*.go", "README.md", false},
+		{"internal/*.go", "internal/x.go", true},
+		{"internal/*.go", "internal/ui/menu.go", false},
+		{"internal/**/*.go", "internal/ui/menu.go", true},
+		{"internal/**/*.go", "internal/x.go", true},
+		{"**/testdata/*", "a/b/testdata/f.txt", true},
+		{".git", ".git", true},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestScanIgnoreAndPatterns(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "main.go"), "package main")
+	writeFile(t, filepath.Join(root, "README.md"), "readme")
+	writeFile(t, filepath.Join(root, ".git", "HEAD"), "ref")
+	writeFile(t, filepath.Join(root, "dist", "out.go"), "package out")
+
+	snap := Scan(Options{Root: root, Ignore: DefaultIgnore})
+	if _, ok := snap["main.go"]; !ok {
+		t.Error("expected main.go to be watched")
+	}
+	if _, ok := snap[".git/HEAD"]; ok {
+		t.Error(".git should be ignored")
+	}
+	if _, ok := snap["dist/out.go"]; ok {
+		t.Error("dist should be ignored")
+	}
+
+	snap = Scan(Options{Root: root, Patterns: []string{"*.go"}, Ignore: DefaultIgnore})
+	if len(snap) != 1 {
+		t.Errorf("expected only main.go, got %v", snap)
+	}
+}
+
+func TestChanged(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "a.txt"), "a")
+	writeFile(t, filepath.Join(root, "b.txt"), "b")
+	before := Scan(Options{Root: root})
+
+	writeFile(t, filepath.Join(root, "a.txt"), "changed")
+	os.Remove(filepath.Join(root, "b.txt"))
+	writeFile(t, filepath.Join(root, "c.txt"), "c")
+	after := Scan(Options{Root: root})
+
+	got := Changed(before, after)
+	want := []string{"a.txt", "b.txt", "c.txt"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestWatchDebouncesBursts(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "a.txt"), "a")
+
+	stop := make(chan struct{})
+	defer close(stop)
+	changes := Watch(Options{Root: root, Interval: 10 * time.Millisecond, Debounce: 50 * time.Millisecond}, stop)
+
+	time.Sleep(30 * time.Millisecond)
+	writeFile(t, filepath.Join(root, "a.txt"), "aa")
+	writeFile(t, filepath.Join(root, "b.txt"), "b")
+
+	select {
+	case batch := <-changes:
+		if len(batch) != 2 {
+			t.Errorf("expected both changes in a single batch, got %v", batch)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for changes")
+	}
+}
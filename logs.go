@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/subut0n/mk/internal/history"
+	"github.com/subut0n/mk/internal/i18n"
+	"github.com/subut0n/mk/internal/logs"
+	"github.com/subut0n/mk/internal/runner"
+	"github.com/subut0n/mk/internal/ui"
+)
+
+// projectLogDir returns the log directory of the current project.
+func projectLogDir() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return logs.ProjectDir(wd)
+}
+
+// logExcluded reports whether runs of target in the current directory are
+// kept out of the history, and so must not leave a log either.
+func logExcluded(target string) bool {
+	wd, _ := os.Getwd()
+	return historyPrivacy().Excluded(history.Entry{Target: target, Directory: wd})
+}
+
+// createRunLog opens the log file for a run starting now. Logging is best
+// effort: nil is returned when the file cannot be created or the run is
+// excluded from the history.
+func createRunLog(target string) *os.File {
+	if logExcluded(target) {
+		return nil
+	}
+	dir, err := projectLogDir()
+	if err != nil {
+		return nil
+	}
+	f, err := logs.Create(dir, target, time.Now())
+	if err != nil {
+		return nil
+	}
+	return f
+}
+
+// writeRunLog saves the captured output of a finished run and returns the
+// log path, or "" if it could not be written or the run is excluded from the
+// history.
+func writeRunLog(res runner.Result) string {
+	if logExcluded(res.Job.Target) {
+		return ""
+	}
+	dir, err := projectLogDir()
+	if err != nil {
+		return ""
+	}
+	f, err := logs.Create(dir, res.Job.Target, res.StartedAt)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	if _, err := f.Write(res.Log); err != nil {
+		return ""
+	}
+	return f.Name()
+}
+
+// pruneRunLogs applies the configured retention limits to the current project's logs.
+func pruneRunLogs() {
+	keep, maxAge := logs.DefaultKeep, logs.DefaultMaxAge
+	if activeConfig != nil {
+		if activeConfig.Config.LogKeep > 0 {
+			keep = activeConfig.Config.LogKeep
+		}
+		if activeConfig.Config.LogMaxAgeDays > 0 {
+			maxAge = time.Duration(activeConfig.Config.LogMaxAgeDays) * 24 * time.Hour
+		}
+	}
+	if dir, err := projectLogDir(); err == nil {
+		_ = logs.Prune(dir, keep, maxAge)
+	}
+}
+
+// showLog handles `mk --log [target]`: the latest log of the current project
+// (or of the given target) is opened in $PAGER.
+func showLog(args []string) {
+	m := i18n.Get()
+	target := ""
+	if len(args) > 0 {
+		target = args[0]
+	}
+
+	dir, err := projectLogDir()
+	if err != nil {
+		fatal(m.ErrGeneric, err)
+	}
+	path, err := logs.Latest(dir, target)
+	if err != nil {
+		if target == "" {
+			fatal("%s", m.ErrNoLogs)
+		}
+		fatal(m.ErrNoLogFor, target)
+	}
+	openPager(path)
+}
+
+// openPager shows a file in $PAGER (less -R by default). The file is copied
+// to stdout when stdout is not a terminal or no pager can be started.
+func openPager(path string) {
+	if ui.IsTerminal(os.Stdout) {
+		pager := os.Getenv("PAGER")
+		if pager == "" {
+			pager = "less -R"
+		}
+		fields := strings.Fields(pager)
+		cmd := exec.Command(fields[0], append(fields[1:], path)...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err == nil {
+			return
+		} else if _, ok := err.(*exec.ExitError); ok {
+			return
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		fatal(i18n.Get().ErrGeneric, err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(os.Stdout, f); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
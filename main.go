@@ -1,7 +1,9 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -13,60 +15,96 @@ import (
 	"github.com/subut0n/mk/internal/history"
 	"github.com/subut0n/mk/internal/i18n"
 	"github.com/subut0n/mk/internal/parser"
+	"github.com/subut0n/mk/internal/runner"
+	"github.com/subut0n/mk/internal/safefile"
+	"github.com/subut0n/mk/internal/theme"
 	"github.com/subut0n/mk/internal/ui"
 )
 
 // Version is set via -ldflags "-X main.Version=x.y.z"
 var Version = "dev"
 
+// activeConfig is the configuration loaded by loadConfigAndSetLang or
+// withConfig, used by helpers that run after the command has been dispatched.
+var activeConfig *config.Manager
+
+// colorDepth is the number of colors the terminal displays, to which theme
+// colors are downgraded.
+var colorDepth = theme.DetectDepth()
+
+// lightBackground is set by applyTheme when the terminal has a light
+// background, to use the light variant of themes.
+var lightBackground bool
+
+// backgroundQueried is set once queryBackground has asked the terminal.
+var backgroundQueried bool
+
 func fatal(format string, args ...any) {
 	fmt.Fprintf(os.Stderr, ansi.Red+format+ansi.Reset+"\n", args...)
 	os.Exit(1)
 }
 
-// getPalette returns the ANSI color codes for the given color scheme.
+// getPalette returns the ANSI color codes of the palette of the given color
+// scheme, or a single empty code while colors are disabled.
 func getPalette(scheme config.ColorScheme) []string {
-	switch scheme {
-	case config.ColorSchemeDeuteranopia:
-		return []string{
-			"\033[34m", // Blue
-			"\033[33m", // Yellow
-			"\033[36m", // Cyan
-			"\033[35m", // Magenta
-			"\033[94m", // Bright Blue
-			"\033[93m", // Bright Yellow
-		}
-	case config.ColorSchemeTritanopia:
-		return []string{
-			"\033[31m", // Red
-			"\033[35m", // Magenta
-			"\033[32m", // Green
-			"\033[91m", // Bright Red
-			"\033[95m", // Bright Magenta
-			"\033[92m", // Bright Green
-		}
-	case config.ColorSchemeHighContrast:
-		return []string{
-			"\033[91m", // Bright Red
-			"\033[93m", // Bright Yellow
-			"\033[92m", // Bright Green
-			"\033[96m", // Bright Cyan
-			"\033[94m", // Bright Blue
-			"\033[95m", // Bright Magenta
-		}
-	default: // rainbow
-		return []string{
-			"\033[31m", // Red
-			"\033[33m", // Yellow
-			"\033[32m", // Green
-			"\033[36m", // Cyan
-			"\033[34m", // Blue
-			"\033[35m", // Magenta
-		}
+	var themes map[string]theme.Theme
+	if activeConfig != nil {
+		themes = activeConfig.Config.Themes
+	}
+	return config.ThemeOf(scheme, themes).Resolve(lightBackground).PaletteCodes(colorDepth)
+}
+
+// applyTheme makes the theme selected in cfg the interface colors, in its
+// variant for the background of the terminal as far as it is known without
+// asking the terminal: see queryBackground.
+func applyTheme(cfg *config.Manager) {
+	lightBackground = isLightBackground(cfg.Config.Background)
+	cfg.Theme().Resolve(lightBackground).Apply(colorDepth)
+}
+
+// isLightBackground reports whether the terminal has a light background, as
+// set in the configuration or else as told by COLORFGBG. Without colors, or
+// without either, the background is assumed to be dark.
+func isLightBackground(setting config.Background) bool {
+	switch setting {
+	case config.BackgroundLight:
+		return true
+	case config.BackgroundDark:
+		return false
+	}
+	if !ansi.ColorsEnabled() {
+		return false
+	}
+	light, _ := theme.BackgroundFromEnv()
+	return light
+}
+
+// queryBackground asks the terminal for its background color with an OSC 11
+// query when the configuration leaves the background to detection, and
+// applies the theme again for the answer. The query reads from the terminal
+// for up to a second, so it is only made once a menu or the setup is about to
+// be drawn, and at most once.
+func queryBackground(cfg *config.Manager) {
+	if backgroundQueried || !ansi.ColorsEnabled() {
+		return
+	}
+	backgroundQueried = true
+	if s := cfg.Config.Background; s == config.BackgroundLight || s == config.BackgroundDark {
+		return
+	}
+	spec, ok := ui.QueryBackground(time.Second)
+	if !ok {
+		return
+	}
+	if c, err := theme.ParseXColor(spec); err == nil {
+		lightBackground = c.IsLight()
+		cfg.Theme().Resolve(lightBackground).Apply(colorDepth)
 	}
 }
 
 func main() {
+	ansi.SetColors(ansi.ColorsWanted(ui.IsTerminal(os.Stdout)))
+
 	if len(os.Args) > 1 {
 		arg := os.Args[1]
 		switch arg {
@@ -79,6 +117,10 @@ func main() {
 			printHelp(getPalette(cfg.Config.ColorScheme))
 			return
 		case "--config":
+			if len(os.Args) > 2 && os.Args[2] == "--show" {
+				showConfig(loadConfigAndSetLang())
+				return
+			}
 			runConfigSetup()
 			return
 		case "--lang":
@@ -91,17 +133,42 @@ func main() {
 			runKeysSetup()
 			return
 		case "--history", "-hist":
+			historyCommand(os.Args[2:])
+			return
+		case "--stats":
+			loadConfigAndSetLang()
+			showStats(os.Args[2:])
+			return
+		case "-", "--last":
+			loadConfigAndSetLang()
+			runLast(os.Args[2:])
+			return
+		case "--parallel":
+			runParallel(os.Args[2:])
+			return
+		case "--log":
+			loadConfigAndSetLang()
+			showLog(os.Args[2:])
+			return
+		case "--dry-run", "-n":
 			loadConfigAndSetLang()
-			showHistory()
+			runDryRun(os.Args[2:])
+			return
+		case "--watch", "-w":
+			runWatch(os.Args[2:])
 			return
 		case "init", "config":
+			if arg == "config" && len(os.Args) > 2 {
+				configCommand(os.Args[2:])
+				return
+			}
 			runConfigSetup()
 			return
 		default:
 			// Non-flag argument: treat as a direct target name
 			if !strings.HasPrefix(arg, "-") {
 				loadConfigAndSetLang()
-				runDirectTarget(arg)
+				runDirectTarget(arg, os.Args[2:])
 				return
 			}
 			// Unknown flag: show help and exit
@@ -114,11 +181,12 @@ func main() {
 	// No arguments: launch the interactive menu
 	cfg := loadConfigAndSetLang()
 
-	// First launch: run the initial setup wizard
-	if !cfg.Exists() {
-		result, err := config.RunSetup()
+	// First launch: run the initial setup wizard, unless nobody is there to
+	// answer it
+	if !cfg.Exists() && ui.IsTerminal(os.Stdin) {
+		result, err := config.RunSetup(setupOptions(cfg))
 		if err != nil {
-			fatal(i18n.Get().ErrConfig, err)
+			exitSetup(i18n.Get().ErrConfig, err)
 		}
 		cfg.Config.KeyScheme = result.KeyScheme
 		cfg.Config.Language = result.Language
@@ -145,49 +213,197 @@ func main() {
 		fatal(m.ErrReadMakefile, err)
 	}
 
+	targets = menuTargets(targets, cfg.Config)
 	if len(targets) == 0 {
 		fmt.Fprintf(os.Stderr, "%s%s%s\n", ansi.Red, m.ErrNoTargets, ansi.Reset)
 		fmt.Fprintf(os.Stderr, "%s%s%s\n", ansi.Gray, m.HintAddDoc, ansi.Reset)
 		os.Exit(1)
 	}
 
+	queryBackground(cfg)
 	opts := ui.Options{
 		KeyScheme:     cfg.Config.KeyScheme,
 		ColorPalette:  getPalette(cfg.Config.ColorScheme),
 		CustomUpKey:   cfg.Config.CustomUpKey,
 		CustomDownKey: cfg.Config.CustomDownKey,
+		Preview: func(target string) (string, error) {
+			return runner.DryRun(runner.Job{Makefile: makefilePath, Target: target, Args: makeArgs(nil)})
+		},
+		Order: cfg.Config.MenuOrder,
+	}
+	if hist, err := openHistory(); err == nil {
+		if wd, err := os.Getwd(); err == nil {
+			opts.Frecency = hist.Frecency(wd, time.Now())
+			if recent := hist.InDirectory(wd); len(recent) > 0 {
+				opts.InitialTarget = recent[0].Target
+			}
+		}
 	}
 	result := ui.Run(targets, opts)
 
-	if !result.Confirmed || result.Target == nil {
+	if !result.Confirmed || len(result.Targets) == 0 {
 		fmt.Printf("%s%s%s\n", ansi.Gray, m.Cancelled, ansi.Reset)
 		return
 	}
 
-	executeTarget(makefilePath, result.Target.Name)
+	// Selected targets run one after another; the first failure stops the sequence.
+	for _, t := range result.Targets {
+		executeTarget(makefilePath, t.Name, nil)
+	}
 }
 
-// executeTarget runs a make target, records it in history, and prints the outcome.
-func executeTarget(makefilePath, targetName string) {
+// executeTarget runs a make target, records it in history once it has
+// finished, and prints the outcome.
+func executeTarget(makefilePath, targetName string, args []string) {
 	m := i18n.Get()
 
-	fmt.Printf("%s%s%s%s\n\n", ansi.Bold, ansi.Green, fmt.Sprintf(m.Executing, makefilePath, targetName), ansi.Reset)
+	job := runner.Job{Makefile: makefilePath, Target: targetName, Args: makeArgs(args)}
+	fmt.Printf("%s%s%s%s\n\n", ansi.Bold, ansi.Green, fmt.Sprintf(m.Executing, makefilePath, strings.Join(append([]string{targetName}, job.Args...), " ")), ansi.Reset)
+
+	stdout, stderr := io.Writer(os.Stdout), io.Writer(os.Stderr)
+	logFile := createRunLog(targetName)
+	if logFile != nil {
+		defer logFile.Close()
+		stdout = io.MultiWriter(os.Stdout, logFile)
+		stderr = io.MultiWriter(os.Stderr, logFile)
+	}
+
+	res := runner.Run(job, stdout, stderr, runner.DefaultGrace)
+	logPath := ""
+	if logFile != nil {
+		logPath = logFile.Name()
+		pruneRunLogs()
+	}
+	recordRun(res, logPath)
+	notifyRun(res.Job.Target, runStatus(res), res.ExitCode, res.Duration)
+	exitOnFailure(res, logPath)
+
+	fmt.Printf("\n%s%s%s%s\n", ansi.Bold, ansi.Green, m.Success, ansi.Reset)
+}
+
+// exitOnFailure reports a failed or interrupted run and exits with make's own
+// exit code (128+signal when make was killed), so that scripts wrapping mk can
+// tell failures apart. The path of the run log, if any, is shown as well.
+func exitOnFailure(res runner.Result, logPath string) {
+	m := i18n.Get()
+	switch {
+	case res.Err != nil:
+		fmt.Fprintf(os.Stderr, "\n%s%s%s\n", ansi.Red, fmt.Sprintf(m.ErrCommandFailed, res.Err), ansi.Reset)
+	case res.Interrupted():
+		fmt.Fprintf(os.Stderr, "\n%s%s%s\n", ansi.Red, fmt.Sprintf(m.Interrupted, runner.SignalName(res.Signal), res.ExitCode), ansi.Reset)
+	case res.ExitCode != 0:
+		fmt.Fprintf(os.Stderr, "\n%s%s%s\n", ansi.Red, fmt.Sprintf(m.ErrExitCode, res.ExitCode), ansi.Reset)
+	default:
+		return
+	}
+	if logPath != "" {
+		fmt.Fprintf(os.Stderr, "%s%s%s\n", ansi.Gray, fmt.Sprintf(m.LogSaved, logPath), ansi.Reset)
+	}
+	os.Exit(res.ExitCode)
+}
 
-	cmd := exec.Command("make", "-f", makefilePath, targetName)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
+// recordRun appends a finished run to the history. History is best effort:
+// failing to write it never affects the outcome of the run.
+func recordRun(res runner.Result, logPath string) {
+	hist, err := openHistory()
+	if err != nil {
+		return
+	}
+	makefile, err := filepath.Abs(res.Job.Makefile)
+	if err != nil {
+		makefile = res.Job.Makefile
+	}
+	_ = hist.Record(history.Entry{
+		Target:     res.Job.Target,
+		ExecutedAt: res.StartedAt,
+		Makefile:   makefile,
+		Args:       res.Job.Args,
+		Status:     runStatus(res),
+		ExitCode:   res.ExitCode,
+		DurationMs: res.Duration.Milliseconds(),
+		Commit:     gitCommit(),
+		LogPath:    logPath,
+	})
+}
 
+// openHistory loads the history, warning about a corrupted file that had to
+// be reset.
+func openHistory() (*history.Manager, error) {
 	hist, err := history.New()
-	if err == nil {
-		_ = hist.Add(targetName)
+	if err != nil {
+		return nil, err
+	}
+	warnRecovered(hist.Recovered())
+	if activeConfig != nil {
+		cfg := activeConfig.Config
+		hist.Retention = history.Retention{
+			MaxEntries: cfg.HistoryMaxEntries,
+			MaxAge:     time.Duration(cfg.HistoryMaxAgeDays) * 24 * time.Hour,
+			MaxBytes:   int64(cfg.HistoryMaxSizeMB) << 20,
+		}
+		hist.Privacy = historyPrivacy()
+	}
+	return hist, nil
+}
+
+// historyPrivacy returns the privacy settings of the history, which also
+// decide the runs that get no log.
+func historyPrivacy() history.Privacy {
+	if activeConfig == nil {
+		return history.Privacy{}
+	}
+	cfg := activeConfig.Config
+	return history.Privacy{
+		ExcludeTargets: cfg.HistoryExcludeTargets,
+		ExcludeDirs:    cfg.HistoryExcludeDirs,
+		RedactArgs:     cfg.HistoryRedactArgs,
 	}
+}
 
-	if err := cmd.Run(); err != nil {
-		fatal("\n"+m.ErrCommandFailed, err)
+// warnRecovered tells the user that a corrupted file was reset and where its
+// old content was kept.
+func warnRecovered(rec *safefile.CorruptError) {
+	if rec == nil {
+		return
 	}
+	m := i18n.Get()
+	msg := fmt.Sprintf(m.WarnCorruptFileLost, rec.Path)
+	switch {
+	case rec.Dropped > 0 && rec.Backup != "":
+		msg = fmt.Sprintf(m.WarnCorruptLines, rec.Dropped, rec.Path, rec.Backup)
+	case rec.Backup != "":
+		msg = fmt.Sprintf(m.WarnCorruptFile, rec.Path, rec.Backup)
+	}
+	fmt.Fprintf(os.Stderr, "%s%s%s\n", ansi.Red, msg, ansi.Reset)
+}
 
-	fmt.Printf("\n%s%s%s%s\n", ansi.Bold, ansi.Green, m.Success, ansi.Reset)
+// warnConfig tells the user about the project files and environment
+// variables that were ignored because they could not be used.
+func warnConfig(cfg *config.Manager) {
+	for _, err := range cfg.Warnings() {
+		fmt.Fprintf(os.Stderr, "%s%s%s\n", ansi.Red, fmt.Sprintf(i18n.Get().WarnConfigIgnored, err), ansi.Reset)
+	}
+}
+
+// runStatus classifies a finished run.
+func runStatus(res runner.Result) history.Status {
+	switch {
+	case res.Interrupted():
+		return history.StatusInterrupted
+	case res.ExitCode != 0:
+		return history.StatusFailed
+	}
+	return history.StatusSuccess
+}
+
+// gitCommit returns the short commit hash checked out in the current
+// directory, or "" outside a git repository.
+func gitCommit() string {
+	out, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
 }
 
 // printHelp renders the help text (always in English) using the given color palette.
@@ -198,14 +414,25 @@ func printHelp(palette []string) {
 	}
 	entries := []helpEntry{
 		{"mk", "Interactive menu"},
-		{"mk <target>", "Run a target directly"},
+		{"mk <target> [VAR=value]", "Run a target directly"},
 		{"mk --help, -h", "Show this help"},
 		{"mk --version, -v", "Show version"},
 		{"mk --config", "Configure language, colors and key scheme"},
+		{"mk --config --show", "Show effective settings and their source"},
+		{"mk config get|set|list|reset", "Read or change settings from scripts"},
 		{"mk --lang", "Change language"},
 		{"mk --colors", "Change color scheme"},
 		{"mk --keys", "Change key scheme"},
-		{"mk --history, -hist", "Show execution history"},
+		{"mk --history, -hist", "Browse history (Enter re-runs, x deletes)"},
+		{"mk --history export [opts]", "Export history as JSON or CSV (filterable)"},
+		{"mk --history import <file>", "Merge history exported on another machine"},
+		{"mk --history clear [opts]", "Remove history entries (all, or filtered)"},
+		{"mk -, --last [N]", "Re-run the last (or N-th last) run here"},
+		{"mk --stats [--all] [--json]", "Run counts, success rate and durations"},
+		{"mk --parallel N [targets]", "Run targets concurrently, N at a time"},
+		{"mk --watch, -w <target>", "Re-run a target when files change"},
+		{"mk --dry-run, -n <target>", "Show the commands (make -n), then confirm"},
+		{"mk --log [target]", "Open the latest run log in $PAGER"},
 	}
 
 	fmt.Printf("\n  %s%s🔧 mk%s %s— interactive Makefile runner%s\n\n", ansi.Bold, ansi.Purple, ansi.Reset, ansi.Gray, ansi.Reset)
@@ -213,7 +440,7 @@ func printHelp(palette []string) {
 
 	for i, e := range entries {
 		c := palette[i%len(palette)]
-		fmt.Printf("    %s%-24s%s  %s%s%s\n", c, e.cmd, ansi.Reset, ansi.Gray, e.desc, ansi.Reset)
+		fmt.Printf("    %s%-28s%s  %s%s%s\n", c, e.cmd, ansi.Reset, ansi.Gray, e.desc, ansi.Reset)
 	}
 	fmt.Println()
 }
@@ -229,6 +456,10 @@ func loadConfigAndSetLang() *config.Manager {
 		}}
 	}
 	i18n.Set(cfg.Config.Language)
+	applyTheme(cfg)
+	activeConfig = cfg
+	warnRecovered(cfg.Recovered())
+	warnConfig(cfg)
 	return cfg
 }
 
@@ -239,6 +470,9 @@ func withConfig(fn func(cfg *config.Manager)) {
 		fatal(i18n.Get().ErrGeneric, err)
 	}
 	i18n.Set(cfg.Config.Language)
+	applyTheme(cfg)
+	activeConfig = cfg
+	warnRecovered(cfg.Recovered())
 	fn(cfg)
 	if err := cfg.Save(); err != nil {
 		fatal(i18n.Get().ErrSaveConfig, err)
@@ -247,9 +481,9 @@ func withConfig(fn func(cfg *config.Manager)) {
 
 func runConfigSetup() {
 	withConfig(func(cfg *config.Manager) {
-		result, err := config.RunSetup()
+		result, err := config.RunSetup(setupOptions(cfg))
 		if err != nil {
-			fatal(i18n.Get().ErrGeneric, err)
+			exitSetup(i18n.Get().ErrGeneric, err)
 		}
 		cfg.Config.KeyScheme = result.KeyScheme
 		cfg.Config.Language = result.Language
@@ -267,9 +501,9 @@ func runConfigSetup() {
 
 func runLangSetup() {
 	withConfig(func(cfg *config.Manager) {
-		lang, err := config.RunLangSetup()
+		lang, err := config.RunLangSetup(setupOptions(cfg))
 		if err != nil {
-			fatal(i18n.Get().ErrGeneric, err)
+			exitSetup(i18n.Get().ErrGeneric, err)
 		}
 		cfg.Config.Language = lang
 	})
@@ -277,9 +511,9 @@ func runLangSetup() {
 
 func runColorSetup() {
 	withConfig(func(cfg *config.Manager) {
-		cs, err := config.RunColorSetup()
+		cs, err := config.RunColorSetup(setupOptions(cfg))
 		if err != nil {
-			fatal(i18n.Get().ErrGeneric, err)
+			exitSetup(i18n.Get().ErrGeneric, err)
 		}
 		cfg.Config.ColorScheme = cs
 	})
@@ -287,9 +521,9 @@ func runColorSetup() {
 
 func runKeysSetup() {
 	withConfig(func(cfg *config.Manager) {
-		ks, err := config.RunKeysSetup()
+		ks, err := config.RunKeysSetup(setupOptions(cfg))
 		if err != nil {
-			fatal(i18n.Get().ErrGeneric, err)
+			exitSetup(i18n.Get().ErrGeneric, err)
 		}
 		cfg.Config.KeyScheme = ks
 		if ks == config.KeySchemeCustom {
@@ -338,7 +572,9 @@ func captureCustomKeys() (upKey, downKey byte) {
 	}
 }
 
-func runDirectTarget(target string) {
+// runDirectTarget runs `mk <target> [args...]`; extra arguments such as
+// VAR=value are passed to make as-is.
+func runDirectTarget(target string, args []string) {
 	makefilePath := findMakefile()
 	if makefilePath == "" {
 		fatal("%s", i18n.Get().ErrNoMakefile)
@@ -352,38 +588,113 @@ func runDirectTarget(target string) {
 	}
 
 	// Verify the target exists among documented targets
-	found := false
+	target, args = resolveAlias(target, args, targets)
+	if !hasTarget(targets, target) {
+		exitUnknownTarget(target, targets)
+	}
+
+	executeTarget(makefilePath, target, args)
+}
+
+// hasTarget reports whether name is one of the parsed targets.
+func hasTarget(targets []parser.Target, name string) bool {
 	for _, t := range targets {
-		if t.Name == target {
-			found = true
-			break
+		if t.Name == name {
+			return true
 		}
 	}
+	return false
+}
 
-	if !found {
-		fmt.Fprintf(os.Stderr, "%s%s%s\n", ansi.Red, fmt.Sprintf(m.ErrUnknownTarget, target), ansi.Reset)
-		fmt.Fprintf(os.Stderr, "%s%s%s\n", ansi.Gray, m.AvailableTargets, ansi.Reset)
-		for _, t := range targets {
-			desc := ""
-			if t.Description != "" {
-				desc = fmt.Sprintf("  %s%s%s", ansi.Gray, t.Description, ansi.Reset)
-			}
-			fmt.Fprintf(os.Stderr, "  %s•%s %s%s\n", ansi.Purple, ansi.Reset, t.Name, desc)
+// exitUnknownTarget reports an unknown target with the list of available ones and exits.
+func exitUnknownTarget(name string, targets []parser.Target) {
+	m := i18n.Get()
+	fmt.Fprintf(os.Stderr, "%s%s%s\n", ansi.Red, fmt.Sprintf(m.ErrUnknownTarget, name), ansi.Reset)
+	fmt.Fprintf(os.Stderr, "%s%s%s\n", ansi.Gray, m.AvailableTargets, ansi.Reset)
+	for _, t := range targets {
+		desc := ""
+		if t.Description != "" {
+			desc = fmt.Sprintf("  %s%s%s", ansi.Description, t.Description, ansi.Reset)
 		}
+		fmt.Fprintf(os.Stderr, "  %s•%s %s%s\n", ansi.Purple, ansi.Reset, t.Name, desc)
+	}
+	os.Exit(1)
+}
+
+// runDryRun handles `mk --dry-run <target> [VAR=value...]`: it prints the
+// commands make would execute and runs the target only if the user confirms.
+// Aliases are expanded as for `mk <target>`.
+func runDryRun(args []string) {
+	m := i18n.Get()
+	if len(args) == 0 {
+		cfg := loadConfigAndSetLang()
+		printHelp(getPalette(cfg.Config.ColorScheme))
 		os.Exit(1)
 	}
 
-	executeTarget(makefilePath, target)
+	makefilePath := findMakefile()
+	if makefilePath == "" {
+		fatal("%s", m.ErrNoMakefile)
+	}
+	targets, err := parser.ParseMakefile(makefilePath)
+	if err != nil {
+		fatal(m.ErrReadMakefile, err)
+	}
+	target, args := resolveAlias(args[0], args[1:], targets)
+	if !hasTarget(targets, target) {
+		exitUnknownTarget(target, targets)
+	}
+
+	fmt.Printf("%s%s%s%s\n\n", ansi.Bold, ansi.Purple, fmt.Sprintf(m.PreviewTitle, target), ansi.Reset)
+	out, err := runner.DryRun(runner.Job{Makefile: makefilePath, Target: target, Args: makeArgs(args)})
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		fmt.Printf("  %s│%s %s\n", ansi.Gray, ansi.Reset, line)
+	}
+	if err != nil {
+		fatal("\n"+m.PreviewError, err)
+	}
+
+	fmt.Printf("\n%s%s%s", ansi.Gray, m.DryRunConfirm, ansi.Reset)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if !isYes(answer) {
+		fmt.Printf("%s%s%s\n", ansi.Gray, m.Cancelled, ansi.Reset)
+		return
+	}
+	fmt.Println()
+	executeTarget(makefilePath, target, args)
+}
+
+// isYes reports whether a confirmation answer is affirmative in any supported
+// language (yes, oui, sí, ja).
+func isYes(answer string) bool {
+	a := strings.ToLower(strings.TrimSpace(answer))
+	if a == "" {
+		return false
+	}
+	switch []rune(a)[0] {
+	case 'y', 'o', 's', 'j':
+		return true
+	}
+	return false
 }
 
 func findMakefile() string {
-	for _, name := range []string{"Makefile", "makefile", "GNUmakefile"} {
+	names := []string{"Makefile", "makefile", "GNUmakefile"}
+	if activeConfig != nil {
+		if c := activeConfig.Config; c.Makefile != "" {
+			return relativePath(c.Makefile)
+		} else if len(c.MakefileNames) > 0 {
+			names = c.MakefileNames
+		}
+	}
+
+	for _, name := range names {
 		if _, err := os.Stat(name); err == nil {
 			return name
 		}
 	}
 
-	prefixes := []string{"Makefile", "makefile", "GNUmakefile"}
+	prefixes := names
 	entries, err := os.ReadDir(".")
 	if err != nil {
 		return ""
@@ -405,7 +716,7 @@ func findMakefile() string {
 
 func showHistory() {
 	m := i18n.Get()
-	hist, err := history.New()
+	hist, err := openHistory()
 	if err != nil {
 		fatal(m.ErrReadHistory, err)
 	}
@@ -419,15 +730,46 @@ func showHistory() {
 	fmt.Printf("%s%s%s%s\n\n", ansi.Bold, ansi.Purple, m.HistoryTitle, ansi.Reset)
 	for i, e := range entries {
 		age := formatAge(e.ExecutedAt)
-		fmt.Printf("  %s%2d.%s  %-30s  %s%s  %s%s\n",
+		duration := ""
+		if e.Status != history.StatusUnknown {
+			duration = formatDuration(e.Duration())
+		}
+		fmt.Printf("  %s%2d.%s %s  %-30s  %s%-8s  %s  %s%s\n",
 			ansi.Purple, i+1, ansi.Reset,
-			fmt.Sprintf("%s%s%s", ansi.Bold, e.Target, ansi.Reset),
-			ansi.Gray, age,
+			statusIcon(e),
+			fmt.Sprintf("%s%s%s", ansi.Bold, strings.Join(append([]string{e.Target}, e.Args...), " "), ansi.Reset),
+			ansi.Gray, duration, age,
 			e.Directory, ansi.Reset,
 		)
 	}
 }
 
+// statusIcon returns a colored symbol for the outcome of a history entry.
+func statusIcon(e history.Entry) string {
+	switch e.Status {
+	case history.StatusSuccess:
+		return ansi.Green + "✓" + ansi.Reset
+	case history.StatusFailed:
+		return ansi.Red + "✗" + ansi.Reset
+	case history.StatusInterrupted:
+		return ansi.Red + "⚠" + ansi.Reset
+	default:
+		return ansi.Gray + "·" + ansi.Reset
+	}
+}
+
+// formatDuration renders a run duration with a precision suited to its length.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	default:
+		return d.Truncate(time.Second).String()
+	}
+}
+
 func formatAge(t time.Time) string {
 	m := i18n.Get()
 	d := time.Since(t)
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/subut0n/mk/internal/history"
+	"github.com/subut0n/mk/internal/i18n"
+	"github.com/subut0n/mk/internal/notify"
+	"github.com/subut0n/mk/internal/ui"
+)
+
+// notifyOptions builds the notification settings from the configuration.
+func notifyOptions() notify.Options {
+	opts := notify.Options{After: notify.DefaultAfter, Bell: true, OSC: notify.OSCBoth}
+	if activeConfig == nil {
+		return opts
+	}
+	cfg := activeConfig.Config
+	if cfg.NotifyAfterSeconds > 0 {
+		opts.After = time.Duration(cfg.NotifyAfterSeconds) * time.Second
+	}
+	opts.Bell = cfg.NotifyBell
+	opts.OSC = cfg.NotifyOSC
+	opts.Command = cfg.NotifyCommand
+	return opts
+}
+
+// notifyRun announces a finished run that took longer than the configured
+// threshold, so that users who switched windows learn it is done.
+// Notifications are best effort and never affect the outcome of the run.
+func notifyRun(target string, status history.Status, exitCode int, d time.Duration) {
+	opts := notifyOptions()
+	if !opts.Due(d) {
+		return
+	}
+
+	m := i18n.Get()
+	var msg string
+	switch status {
+	case history.StatusInterrupted:
+		msg = fmt.Sprintf(m.NotifyInterrupted, target, formatDuration(d))
+	case history.StatusFailed:
+		msg = fmt.Sprintf(m.NotifyFailed, target, formatDuration(d), exitCode)
+	default:
+		msg = fmt.Sprintf(m.NotifySuccess, target, formatDuration(d))
+	}
+
+	var term io.Writer
+	if ui.IsTerminal(os.Stderr) {
+		term = os.Stderr
+	}
+	ev := notify.Event{Target: target, Status: string(status), Duration: d, Message: msg}
+	_ = notify.Send(opts, ev, term)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/subut0n/mk/internal/ansi"
+	"github.com/subut0n/mk/internal/history"
+	"github.com/subut0n/mk/internal/i18n"
+	"github.com/subut0n/mk/internal/parser"
+	"github.com/subut0n/mk/internal/runner"
+	"github.com/subut0n/mk/internal/ui"
+)
+
+// runParallel handles `mk --parallel N [target...]`. Without targets, the
+// interactive menu is shown so that several targets can be selected.
+func runParallel(args []string) {
+	cfg := loadConfigAndSetLang()
+	m := i18n.Get()
+
+	if len(args) == 0 {
+		fatal("%s", m.ErrParallelCount)
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 {
+		fatal("%s", m.ErrParallelCount)
+	}
+	names := args[1:]
+
+	makefilePath := findMakefile()
+	if makefilePath == "" {
+		fatal("%s", m.ErrNoMakefile)
+	}
+
+	targets, err := parser.ParseMakefile(makefilePath)
+	if err != nil {
+		fatal(m.ErrReadMakefile, err)
+	}
+
+	if len(names) == 0 {
+		if len(targets) == 0 {
+			fatal("%s", m.ErrNoTargets)
+		}
+		queryBackground(cfg)
+		result := ui.Run(menuTargets(targets, cfg.Config), ui.Options{
+			KeyScheme:     cfg.Config.KeyScheme,
+			ColorPalette:  getPalette(cfg.Config.ColorScheme),
+			CustomUpKey:   cfg.Config.CustomUpKey,
+			CustomDownKey: cfg.Config.CustomDownKey,
+		})
+		if !result.Confirmed || len(result.Targets) == 0 {
+			fmt.Printf("%s%s%s\n", ansi.Gray, m.Cancelled, ansi.Reset)
+			return
+		}
+		for _, t := range result.Targets {
+			names = append(names, t.Name)
+		}
+	}
+
+	for _, name := range names {
+		if !hasTarget(targets, name) {
+			exitUnknownTarget(name, targets)
+		}
+	}
+
+	palette := getPalette(cfg.Config.ColorScheme)
+	jobs := make([]runner.Job, len(names))
+	for i, name := range names {
+		jobs[i] = runner.Job{Makefile: makefilePath, Target: name, Args: makeArgs(nil)}
+	}
+
+	fmt.Printf("%s%s%s%s\n\n", ansi.Bold, ansi.Green, fmt.Sprintf(m.ExecutingParallel, n, strings.Join(names, " ")), ansi.Reset)
+
+	start := time.Now()
+	results := runner.RunParallel(jobs, n, palette, os.Stdout)
+	for _, r := range results {
+		recordRun(r, writeRunLog(r))
+	}
+	pruneRunLogs()
+	printParallelSummary(results, palette)
+	notifyRun(strings.Join(names, " "), batchStatus(results), runner.ExitStatus(results), time.Since(start))
+
+	if code := runner.ExitStatus(results); code != 0 {
+		os.Exit(code)
+	}
+}
+
+// printParallelSummary prints one status line per job, followed by the full
+// output of each failed job so it can be read without interleaving.
+func printParallelSummary(results []runner.Result, palette []string) {
+	m := i18n.Get()
+
+	fmt.Printf("\n%s%s%s%s\n", ansi.Bold, ansi.Purple, m.ParallelSummary, ansi.Reset)
+	for i, r := range results {
+		c := palette[i%len(palette)]
+		status := ansi.Green + "✓" + ansi.Reset
+		switch {
+		case r.Interrupted():
+			status = ansi.Red + "⚠" + ansi.Reset
+		case r.ExitCode != 0:
+			status = ansi.Red + "✗" + ansi.Reset
+		}
+		fmt.Printf("  %s %s%-24s%s %s%s%s\n", status, c, r.Job.Target, ansi.Reset, ansi.Gray, formatDuration(r.Duration), ansi.Reset)
+	}
+
+	for _, r := range results {
+		if r.ExitCode == 0 || (r.Interrupted() && len(r.Log) == 0 && r.Err == nil) {
+			continue
+		}
+		fmt.Printf("\n%s%s%s\n", ansi.Red, fmt.Sprintf(m.ParallelFailedLog, r.Job.Target, r.ExitCode), ansi.Reset)
+		if r.Err != nil {
+			fmt.Printf("%v\n", r.Err)
+			continue
+		}
+		os.Stdout.Write(r.Log)
+	}
+}
+
+// batchStatus classifies a parallel run: interrupted if any job was
+// interrupted, failed if any job failed.
+func batchStatus(results []runner.Result) history.Status {
+	status := history.StatusSuccess
+	for _, r := range results {
+		switch runStatus(r) {
+		case history.StatusInterrupted:
+			return history.StatusInterrupted
+		case history.StatusFailed:
+			status = history.StatusFailed
+		}
+	}
+	return status
+}
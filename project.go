@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/subut0n/mk/internal/config"
+	"github.com/subut0n/mk/internal/parser"
+)
+
+// menuTargets returns the targets offered in menus: undocumented targets are
+// left out with documented_only, hidden_targets are left out, and the
+// targets listed in target_order come first. Hidden targets can still be run
+// by name.
+func menuTargets(targets []parser.Target, c config.Config) []parser.Target {
+	var visible []parser.Target
+	for _, t := range targets {
+		if c.DocumentedOnly && t.Description == "" {
+			continue
+		}
+		if slices.ContainsFunc(c.HiddenTargets, func(pattern string) bool {
+			ok, _ := path.Match(pattern, t.Name)
+			return ok
+		}) {
+			continue
+		}
+		visible = append(visible, t)
+	}
+
+	rank := func(t parser.Target) int {
+		if i := slices.Index(c.TargetOrder, t.Name); i >= 0 {
+			return i
+		}
+		return len(c.TargetOrder)
+	}
+	sort.SliceStable(visible, func(i, j int) bool {
+		return rank(visible[i]) < rank(visible[j])
+	})
+	return visible
+}
+
+// resolveAlias expands name when it is not a target but an alias of the
+// configuration, such as "dp": "deploy ENV=prod". The arguments of the alias
+// come before args, so that the command line can override them.
+func resolveAlias(name string, args []string, targets []parser.Target) (string, []string) {
+	if hasTarget(targets, name) || activeConfig == nil {
+		return name, args
+	}
+	fields := strings.Fields(activeConfig.Config.Aliases[name])
+	if len(fields) == 0 {
+		return name, args
+	}
+	return fields[0], append(fields[1:], args...)
+}
+
+// makeArgs adds the default_vars of the configuration to the arguments of a
+// make run, unless args sets the same variable.
+func makeArgs(args []string) []string {
+	if activeConfig == nil || len(activeConfig.Config.DefaultVars) == 0 {
+		return args
+	}
+	set := make(map[string]bool)
+	for _, arg := range args {
+		if name, _, ok := strings.Cut(arg, "="); ok {
+			set[name] = true
+		}
+	}
+
+	var defaults []string
+	for name, value := range activeConfig.Config.DefaultVars {
+		if !set[name] {
+			defaults = append(defaults, name+"="+value)
+		}
+	}
+	sort.Strings(defaults)
+	return append(defaults, args...)
+}
+
+// relativePath returns path relative to the current directory when it lies
+// below it, and path unchanged otherwise.
+func relativePath(p string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return p
+	}
+	if rel, err := filepath.Rel(wd, p); err == nil && filepath.IsLocal(rel) {
+		return rel
+	}
+	return p
+}
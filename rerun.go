@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/subut0n/mk/internal/ansi"
+	"github.com/subut0n/mk/internal/config"
+	"github.com/subut0n/mk/internal/history"
+	"github.com/subut0n/mk/internal/i18n"
+	"github.com/subut0n/mk/internal/parser"
+	"github.com/subut0n/mk/internal/ui"
+)
+
+// runLast handles `mk -` and `mk --last [N]`: the N-th most recent run
+// recorded in the current directory (1, the latest, by default) is run again
+// with the same Makefile and arguments.
+func runLast(args []string) {
+	m := i18n.Get()
+
+	n := 1
+	if len(args) > 0 {
+		v, err := strconv.Atoi(args[0])
+		if err != nil || v < 1 {
+			fatal(m.ErrLastUsage, args[0])
+		}
+		n = v
+	}
+
+	hist, err := openHistory()
+	if err != nil {
+		fatal(m.ErrReadHistory, err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		fatal(m.ErrReadHistory, err)
+	}
+
+	entries := hist.InDirectory(wd)
+	switch {
+	case len(entries) == 0:
+		fatal("%s", m.ErrNoLastRun)
+	case n > len(entries):
+		fatal(m.ErrLastRange, len(entries))
+	}
+
+	e := entries[n-1]
+	command := strings.Join(append([]string{e.Target}, e.Args...), " ")
+	fmt.Printf("%s%s%s\n", ansi.Gray, fmt.Sprintf(m.Rerunning, command, formatAge(e.ExecutedAt)), ansi.Reset)
+	rerunEntry(e)
+}
+
+// browseHistory handles `mk --history` in a terminal: the history of every
+// project is shown in an interactive list where Enter re-runs the highlighted
+// entry from its recorded directory and Del or x deletes it.
+func browseHistory(cfg *config.Manager) {
+	m := i18n.Get()
+	hist, err := openHistory()
+	if err != nil {
+		fatal(m.ErrReadHistory, err)
+	}
+
+	entries := hist.All()
+	if len(entries) == 0 {
+		fmt.Printf("%s%s%s\n", ansi.Gray, m.HistoryEmpty, ansi.Reset)
+		return
+	}
+
+	queryBackground(cfg)
+	items := make([]ui.Item, len(entries))
+	for i, e := range entries {
+		detail := formatAge(e.ExecutedAt) + "  " + e.Directory
+		if e.Status != history.StatusUnknown {
+			detail = fmt.Sprintf("%-8s  %s", formatDuration(e.Duration()), detail)
+		}
+		items[i] = ui.Item{
+			Icon:   statusIcon(e),
+			Label:  strings.Join(append([]string{e.Target}, e.Args...), " "),
+			Detail: detail,
+			Match:  e.Directory,
+		}
+	}
+
+	// Entries are deleted by identity, so indices into the original slice
+	// stay valid as the list shrinks.
+	snapshot := append([]history.Entry(nil), entries...)
+	idx, ok := ui.RunList(items, ui.ListOptions{
+		Options: ui.Options{
+			KeyScheme:     cfg.Config.KeyScheme,
+			ColorPalette:  getPalette(cfg.Config.ColorScheme),
+			CustomUpKey:   cfg.Config.CustomUpKey,
+			CustomDownKey: cfg.Config.CustomDownKey,
+		},
+		Title:   m.HistoryTitle,
+		Actions: m.HistoryActions,
+		Delete: func(i int) error {
+			return hist.Delete(snapshot[i])
+		},
+	})
+	if !ok {
+		return
+	}
+
+	e := snapshot[idx]
+	if err := enterProject(e.Directory); err != nil {
+		fatal(m.ErrHistoryDirGone, e.Directory)
+	}
+	command := strings.Join(append([]string{e.Target}, e.Args...), " ")
+	fmt.Printf("%s%s%s\n", ansi.Gray, fmt.Sprintf(m.Rerunning, command, formatAge(e.ExecutedAt)), ansi.Reset)
+	rerunEntry(e)
+}
+
+// enterProject makes dir the current directory and loads the configuration
+// that applies there, so that a run recorded in another project gets that
+// project's Makefile, aliases and default_vars.
+func enterProject(dir string) error {
+	if err := os.Chdir(dir); err != nil {
+		return err
+	}
+	loadConfigAndSetLang()
+	return nil
+}
+
+// rerunEntry runs a recorded entry again from the current directory. The
+// recorded Makefile is used when it still exists, shown relative to the
+// current directory when possible. Runs whose arguments were redacted cannot
+// be repeated.
+func rerunEntry(e history.Entry) {
+	m := i18n.Get()
+	if e.HasRedactedArgs() {
+		fatal(m.ErrRerunRedacted, strings.Join(append([]string{e.Target}, e.Args...), " "))
+	}
+
+	makefilePath := ""
+	if e.Makefile != "" {
+		if _, err := os.Stat(e.Makefile); err == nil {
+			makefilePath = relativePath(e.Makefile)
+		}
+	}
+	if makefilePath == "" {
+		makefilePath = findMakefile()
+	}
+	if makefilePath == "" {
+		fatal("%s", m.ErrNoMakefile)
+	}
+
+	targets, err := parser.ParseMakefile(makefilePath)
+	if err != nil {
+		fatal(m.ErrReadMakefile, err)
+	}
+	if !hasTarget(targets, e.Target) {
+		exitUnknownTarget(e.Target, targets)
+	}
+
+	executeTarget(makefilePath, e.Target, e.Args)
+}
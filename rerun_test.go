@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/subut0n/mk/internal/history"
+)
+
+func TestRerunInOtherProject(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("MK_CONFIG", "")
+
+	// Two projects whose .mk.json pass different default_vars to make.
+	projects := map[string]string{}
+	for _, name := range []string{"api", "web"} {
+		dir := t.TempDir()
+		write(t, filepath.Join(dir, "Makefile"), "show:\n\t@echo $(ENV) > out.txt\n")
+		write(t, filepath.Join(dir, ".mk.json"), `{"default_vars": {"ENV": "`+name+`"}}`)
+		projects[name] = dir
+	}
+
+	t.Chdir(projects["api"])
+	loadConfigAndSetLang()
+	t.Cleanup(func() { activeConfig = nil })
+
+	e := history.Entry{Target: "show", Directory: projects["web"], Makefile: filepath.Join(projects["web"], "Makefile")}
+	if err := enterProject(e.Directory); err != nil {
+		t.Fatal(err)
+	}
+	rerunEntry(e)
+
+	out, err := os.ReadFile(filepath.Join(projects["web"], "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "web" {
+		t.Errorf("ENV = %q in the web project, want web", got)
+	}
+}
+
+func write(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
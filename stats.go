@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/subut0n/mk/internal/ansi"
+	"github.com/subut0n/mk/internal/i18n"
+	"github.com/subut0n/mk/internal/stats"
+)
+
+// showStats handles `mk --stats [--all] [--json]`: run counts, success rate,
+// durations and trends per target, for the current directory or, with --all,
+// for every project in the history.
+func showStats(args []string) {
+	m := i18n.Get()
+
+	all, asJSON := false, false
+	for _, a := range args {
+		switch a {
+		case "--all":
+			all = true
+		case "--json":
+			asJSON = true
+		default:
+			fatal(m.ErrStatsUsage, a)
+		}
+	}
+
+	hist, err := openHistory()
+	if err != nil {
+		fatal(m.ErrReadHistory, err)
+	}
+
+	entries := hist.All()
+	if !all {
+		wd, err := os.Getwd()
+		if err != nil {
+			fatal(m.ErrReadHistory, err)
+		}
+		entries = hist.InDirectory(wd)
+	}
+	result := stats.Compute(entries, time.Now())
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			fatal(m.ErrGeneric, err)
+		}
+		return
+	}
+
+	if len(result) == 0 {
+		msg := m.StatsEmpty
+		if all {
+			msg = m.HistoryEmpty
+		}
+		fmt.Printf("%s%s%s\n", ansi.Gray, msg, ansi.Reset)
+		return
+	}
+
+	for i := 0; i < len(result); {
+		j := i
+		for j < len(result) && result[j].Project == result[i].Project {
+			j++
+		}
+		printProjectStats(result[i:j])
+		i = j
+	}
+}
+
+// printProjectStats prints the statistics table of one project.
+func printProjectStats(targets []stats.Target) {
+	m := i18n.Get()
+
+	width := len([]rune(m.StatsColTarget))
+	for _, t := range targets {
+		width = max(width, len([]rune(t.Target)))
+	}
+
+	fmt.Printf("%s%s%s%s\n\n", ansi.Bold, ansi.Purple, fmt.Sprintf(m.StatsTitle, targets[0].Project), ansi.Reset)
+	fmt.Printf("  %s%-*s  %6s  %7s  %8s  %8s  %-14s  %s%s\n", ansi.Gray,
+		width, m.StatsColTarget, m.StatsColRuns, m.StatsColSuccess, m.StatsColMedian, m.StatsColP90,
+		m.StatsColLastFailure, m.StatsColTrend, ansi.Reset)
+
+	for _, t := range targets {
+		rate := fmt.Sprintf("%7s", "—")
+		if t.SuccessRate >= 0 {
+			c := ansi.Green
+			switch {
+			case t.SuccessRate < 0.7:
+				c = ansi.Red
+			case t.SuccessRate < 0.9:
+				c = ansi.Reset
+			}
+			rate = fmt.Sprintf("%s%6.0f%%%s", c, t.SuccessRate*100, ansi.Reset)
+		}
+
+		median, p90 := "—", "—"
+		if t.Succeeded+t.Failed+t.Interrupted > 0 {
+			median = formatDuration(time.Duration(t.MedianMs) * time.Millisecond)
+			p90 = formatDuration(time.Duration(t.P90Ms) * time.Millisecond)
+		}
+
+		lastFailure := "—"
+		if t.LastFailure != nil {
+			lastFailure = formatAge(*t.LastFailure)
+		}
+
+		line := fmt.Sprintf("  %s%-*s%s  %6d  %s  %8s  %8s  %-14s  %s",
+			ansi.Bold, width, t.Target, ansi.Reset,
+			t.Runs, rate, median, p90, lastFailure, sparkline(t.WeeklyRuns))
+		if trend := durationTrend(t.DurationChange); trend != "" {
+			line += " " + trend
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+}
+
+// sparkline draws weekly run counts as block characters scaled to the
+// busiest week; weeks without runs are shown as dots.
+func sparkline(counts []int) string {
+	blocks := []rune("▁▂▃▄▅▆▇█")
+	peak := 0
+	for _, c := range counts {
+		peak = max(peak, c)
+	}
+	var b strings.Builder
+	b.WriteString(ansi.Purple)
+	for _, c := range counts {
+		if c == 0 {
+			b.WriteString(ansi.Gray + "·" + ansi.Purple)
+			continue
+		}
+		b.WriteRune(blocks[(c*len(blocks)-1)/peak])
+	}
+	b.WriteString(ansi.Reset)
+	return b.String()
+}
+
+// durationTrend shows how much slower (red) or faster (green) the recent runs
+// are; changes under 10% are not shown.
+func durationTrend(change float64) string {
+	switch {
+	case change >= 0.1:
+		return fmt.Sprintf("%s+%.0f%%%s", ansi.Red, change*100, ansi.Reset)
+	case change <= -0.1:
+		return fmt.Sprintf("%s%.0f%%%s", ansi.Green, change*100, ansi.Reset)
+	}
+	return ""
+}
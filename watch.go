@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/subut0n/mk/internal/ansi"
+	"github.com/subut0n/mk/internal/i18n"
+	"github.com/subut0n/mk/internal/parser"
+	"github.com/subut0n/mk/internal/runner"
+	"github.com/subut0n/mk/internal/watch"
+)
+
+// runWatch handles `mk --watch <target> [glob...]`: the target is re-run
+// every time a watched file changes.
+func runWatch(args []string) {
+	cfg := loadConfigAndSetLang()
+	m := i18n.Get()
+
+	if len(args) == 0 {
+		fatal("%s", m.ErrWatchUsage)
+	}
+	target := args[0]
+
+	makefilePath := findMakefile()
+	if makefilePath == "" {
+		fatal("%s", m.ErrNoMakefile)
+	}
+	targets, err := parser.ParseMakefile(makefilePath)
+	if err != nil {
+		fatal(m.ErrReadMakefile, err)
+	}
+	if !hasTarget(targets, target) {
+		exitUnknownTarget(target, targets)
+	}
+
+	opts := watch.Options{
+		Root:     ".",
+		Patterns: args[1:],
+		Ignore:   cfg.Config.WatchIgnore,
+		Interval: time.Duration(cfg.Config.WatchIntervalMs) * time.Millisecond,
+		Debounce: time.Duration(cfg.Config.WatchDebounceMs) * time.Millisecond,
+	}
+	if len(opts.Patterns) == 0 {
+		opts.Patterns = cfg.Config.WatchPatterns
+	}
+	if len(opts.Patterns) == 0 {
+		if paths := watchPaths(target, targets); len(paths) > 0 {
+			opts.Paths = append(paths, makefilePath)
+		}
+	}
+	if opts.Ignore == nil {
+		opts.Ignore = watch.DefaultIgnore
+	}
+	opts.Ignore = append(opts.Ignore, buildOutputs(targets)...)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	changes := watch.Watch(opts, stop)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	job := runner.Job{Makefile: makefilePath, Target: target, Args: makeArgs(nil)}
+	var changed []string
+
+	for {
+		fmt.Print(ansi.ClearScreen)
+		fmt.Printf("%s%s%s%s\n", ansi.Bold, ansi.Purple, fmt.Sprintf(m.WatchHeader, target), ansi.Reset)
+		if len(changed) > 0 {
+			fmt.Printf("%s%s%s\n", ansi.Gray, fmt.Sprintf(m.WatchChanged, summarizePaths(changed)), ansi.Reset)
+		}
+		fmt.Println()
+
+		proc, err := runner.Start(job, os.Stdout, os.Stderr)
+		if err != nil {
+			fatal(m.ErrCommandFailed, err)
+		}
+
+		select {
+		case changed = <-changes:
+			proc.Stop(runner.DefaultGrace)
+			continue
+		case <-sigCh:
+			proc.Stop(runner.DefaultGrace)
+			os.Exit(130)
+		case <-proc.Done():
+		}
+
+		res := proc.Result()
+		if res.ExitCode == 0 {
+			fmt.Printf("\n%s%s%s %s(%s)%s\n", ansi.Green, m.Success, ansi.Reset, ansi.Gray, formatDuration(res.Duration), ansi.Reset)
+		} else {
+			fmt.Printf("\n%s%s%s %s(%s)%s\n", ansi.Red, fmt.Sprintf(m.ErrExitCode, res.ExitCode), ansi.Reset, ansi.Gray, formatDuration(res.Duration), ansi.Reset)
+		}
+		fmt.Printf("%s%s%s\n", ansi.Gray, m.WatchWaiting, ansi.Reset)
+
+		select {
+		case changed = <-changes:
+		case <-sigCh:
+			os.Exit(130)
+		}
+	}
+}
+
+// watchPaths resolves the files a target depends on by following its
+// prerequisites through the other rules of the Makefile. Prerequisites that
+// are neither rules nor existing files (e.g. unexpanded variables) are skipped.
+func watchPaths(name string, targets []parser.Target) []string {
+	byName := make(map[string]parser.Target, len(targets))
+	for _, t := range targets {
+		byName[t.Name] = t
+	}
+
+	seen := map[string]bool{}
+	var paths []string
+	var visit func(n string)
+	visit = func(n string) {
+		if seen[n] {
+			return
+		}
+		seen[n] = true
+		if t, ok := byName[n]; ok {
+			for _, p := range t.Prerequisites {
+				visit(p)
+			}
+			return
+		}
+		if strings.Contains(n, "$") {
+			return
+		}
+		if _, err := os.Stat(n); err == nil {
+			paths = append(paths, n)
+		}
+	}
+	visit(name)
+	return paths
+}
+
+// buildOutputs returns ignore rules for files produced by the Makefile:
+// pattern rules (%.o becomes *.o) and rule targets that exist as files.
+func buildOutputs(targets []parser.Target) []string {
+	var rules []string
+	for _, t := range targets {
+		if strings.Contains(t.Name, "%") {
+			rules = append(rules, strings.ReplaceAll(t.Name, "%", "*"))
+			continue
+		}
+		if info, err := os.Stat(t.Name); err == nil && !info.IsDir() {
+			rules = append(rules, t.Name)
+		}
+	}
+	return rules
+}
+
+// summarizePaths lists up to three paths, then how many more changed.
+func summarizePaths(paths []string) string {
+	const max = 3
+	if len(paths) <= max {
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf("%s (+%d)", strings.Join(paths[:max], ", "), len(paths)-max)
+}